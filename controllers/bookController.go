@@ -20,21 +20,31 @@ func NewBookController(repo repositories.BookRepositoryInterface) *BookControlle
 }
 
 // GetBooks godoc
-// @Summary      Lista todos os livros
-// @Description  Retorna todos os livros cadastrados
+// @Summary      Lista os livros
+// @Description  Retorna uma página de livros no envelope {data, total, limit, offset, next_cursor, links}.
+// @Description  Filtros por campo (id, title, author, created_at) com operadores: ?title=x, ?title[ilike]=trecho, ?id[in]=a,b, ?created_at[gte]=2024-01-01, ?created_at[lte]=...
 // @Tags         books
 // @Produce      json
-// @Success      200  {array}   models.Book
+// @Param        sort query string false "Campos separados por vírgula; prefixo - para ordem decrescente" example(-created_at,title)
+// @Param        limit query int false "Itens por página (padrão 50, máximo 500)"
+// @Param        offset query int false "Itens a pular"
+// @Param        cursor query string false "Cursor da próxima página (next_cursor da resposta anterior)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Lista de erros de validação {field, code, message}"
 // @Failure      401  {object}  map[string]string
 // @Router       /books [get]
 // @Security     ApiKeyAuth
 func (c *BookController) GetBooks(ctx *gin.Context) {
-	books, err := c.repo.GetAll()
+	q, ok := parseListQuery(ctx, repositories.BookListSpec)
+	if !ok {
+		return
+	}
+	books, err := c.repo.List(q)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar livros"})
 		return
 	}
-	ctx.JSON(http.StatusOK, books)
+	respondList(ctx, books, q)
 }
 
 // GetBookByID godoc
@@ -85,8 +95,8 @@ func (c *BookController) CreateBook(ctx *gin.Context) {
 		return
 	}
 
-	if newBook.Title == "" || newBook.Author == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Título e autor são obrigatórios"})
+	if errs := newBook.Validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
 		return
 	}
 
@@ -129,6 +139,11 @@ func (c *BookController) UpdateBook(ctx *gin.Context) {
 		return
 	}
 
+	if errs := updatedBook.Validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
+		return
+	}
+
 	updatedBook.ID = id
 
 	if err := c.repo.Update(&updatedBook); err != nil {
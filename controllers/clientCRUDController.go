@@ -1,9 +1,14 @@
 package controllers
 
 import (
+	"encoding/json"
+	"fmt"
 	"minha-api/models"
 	"minha-api/repositories"
+	"minha-api/utils"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -19,19 +24,87 @@ func NewClientCRUDController(repo *repositories.ClientRepository) *ClientCRUDCon
 }
 
 // GetAllClients godoc
-// @Summary      Lista todos os clientes
-// @Description  Retorna todos os clientes cadastrados
+// @Summary      Lista os clientes
+// @Description  Retorna uma página de clientes no envelope {data, total, limit, offset, next_cursor, links}. O filtro por telefone aceita qualquer formato ("(11) 98765-4321", "+55 11 98765-4321", "11987654321") ou apenas parte dos dígitos.
+// @Description  Também aceita filtros por campo (id, name, email, document, person_type, phone_type, address_parts.street, address_parts.city, address_parts.uf, address_parts.cep)
+// @Description  com operadores: ?name=x, ?name[ilike]=trecho, ?person_type[in]=PF,PJ, ?name[gte]=A, ?name[lte]=M
 // @Tags         clients
 // @Produce      json
-// @Success      200 {array} models.Client
+// @Param        phone query string false "Telefone em qualquer formato"
+// @Param        city query string false "Cidade"
+// @Param        uf query string false "UF (sigla do estado)"
+// @Param        cep query string false "CEP"
+// @Param        importJobId query string false "Somente clientes criados ou alterados por último nessa importação"
+// @Param        tag query string false "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)"
+// @Param        withoutTag query string false "Somente clientes sem nenhuma dessas tags"
+// @Param        sort query string false "Campos separados por vírgula; prefixo - para ordem decrescente" example(address_parts.uf,name)
+// @Param        limit query int false "Itens por página (padrão 50, máximo 500)"
+// @Param        offset query int false "Itens a pular"
+// @Param        cursor query string false "Cursor da próxima página (next_cursor da resposta anterior)"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]interface{} "Lista de erros de validação {field, code, message}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
 // @Router       /clients [get]
+// @Security     ApiKeyAuth
 func (c *ClientCRUDController) GetAll(ctx *gin.Context) {
-	clients, err := c.repo.GetAll()
+	q, ok := parseListQuery(ctx, repositories.ClientListSpec)
+	if !ok {
+		return
+	}
+	clients, err := c.repo.List(clientFilterFromQuery(ctx), q)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
 		return
 	}
-	ctx.JSON(http.StatusOK, clients)
+	respondList(ctx, clients, q)
+}
+
+// Limites de resultados de GET /clients/search
+const (
+	defaultClientSearchLimit = 20
+	maxClientSearchLimit     = 100
+)
+
+// SearchClients godoc
+// @Summary      Busca clientes
+// @Description  Busca por trecho do nome (sem diferenciar acentos e tolerando erros de digitação), do email e dos dígitos do telefone ou do CPF/CNPJ.
+// @Description  Os resultados vêm do mais para o menos relevante, com a relevância em rank.
+// @Tags         clients
+// @Produce      json
+// @Param        q query string true "Texto buscado" example(acme comercio)
+// @Param        limit query int false "Máximo de resultados (padrão 20, máximo 100)"
+// @Param        tag query string false "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)"
+// @Param        withoutTag query string false "Somente clientes sem nenhuma dessas tags"
+// @Success      200 {array} repositories.ClientSearchResult
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/search [get]
+// @Security     ApiKeyAuth
+func (c *ClientCRUDController) Search(ctx *gin.Context) {
+	q := strings.TrimSpace(ctx.Query("q"))
+	if len(utils.SearchWords(q)) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Informe o texto da busca em q"})
+		return
+	}
+	limit := defaultClientSearchLimit
+	if s := ctx.Query("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um inteiro positivo"})
+			return
+		}
+		limit = min(v, maxClientSearchLimit)
+	}
+	results, err := c.repo.Search(q, clientFilterFromQuery(ctx), limit)
+	if err != nil {
+		fmt.Printf("[ERRO] Falha na busca de clientes %q: %v\n", q, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+		return
+	}
+	if results == nil {
+		results = []repositories.ClientSearchResult{}
+	}
+	ctx.JSON(http.StatusOK, results)
 }
 
 // GetClientByID godoc
@@ -43,23 +116,17 @@ func (c *ClientCRUDController) GetAll(ctx *gin.Context) {
 // @Success      200 {object} models.Client
 // @Failure      400 {object} map[string]string
 // @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
 // @Router       /clients/{id} [get]
+// @Security     ApiKeyAuth
 func (c *ClientCRUDController) GetByID(ctx *gin.Context) {
-	id := ctx.Param("id")
-	if _, err := uuid.Parse(id); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+	id, ok := clientIDParam(ctx)
+	if !ok {
 		return
 	}
-	client, err := c.repo.GetByID(id)
-	if err != nil {
-		if strings.Contains(err.Error(), "record not found") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cliente"})
-		}
-		return
+	if client, ok := c.find(ctx, id); ok {
+		ctx.JSON(http.StatusOK, client)
 	}
-	ctx.JSON(http.StatusOK, client)
 }
 
 // CreateClient godoc
@@ -70,46 +137,199 @@ func (c *ClientCRUDController) GetByID(ctx *gin.Context) {
 // @Produce      json
 // @Param        client body models.Client true "Dados do cliente"
 // @Success      201 {object} models.Client
-// @Failure      400 {object} map[string]string
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      409 {object} map[string]string "Documento ou e-mail já usado por outro cliente: {error, field, client_id}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
 // @Router       /clients [post]
+// @Security     ApiKeyAuth
 func (c *ClientCRUDController) Create(ctx *gin.Context) {
 	var client models.Client
 	if err := ctx.ShouldBindJSON(&client); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
 		return
 	}
-	if err := c.repo.Create(&client); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar cliente"})
+	client.ImportJobID = "" // preenchido somente pela importação
+	client.Tags = nil       // alteradas somente em POST /clients/tags
+	if errs := client.Validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
+		return
+	}
+	if err := c.repo.WithAudit(requestAudit(ctx)).Create(&client); err != nil {
+		if !respondClientConflict(ctx, err) {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar cliente"})
+		}
 		return
 	}
 	ctx.JSON(http.StatusCreated, client)
 }
 
 // UpdateClient godoc
-// @Summary      Atualiza um cliente
-// @Description  Atualiza os dados de um cliente pelo ID
+// @Summary      Substitui um cliente
+// @Description  Substitui todos os dados do cliente pelos do corpo: campos ausentes ou vazios ficam vazios.
+// @Description  Para alterar só alguns campos use PATCH /clients/{id}. Retorna o cliente como foi gravado.
 // @Tags         clients
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "ID do cliente"
 // @Param        client body models.Client true "Dados do cliente"
 // @Success      200 {object} models.Client
-// @Failure      400 {object} map[string]string
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
 // @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Documento ou e-mail já usado por outro cliente: {error, field, client_id}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
 // @Router       /clients/{id} [put]
+// @Security     ApiKeyAuth
 func (c *ClientCRUDController) Update(ctx *gin.Context) {
-	id := ctx.Param("id")
+	id, ok := clientIDParam(ctx)
+	if !ok {
+		return
+	}
 	var client models.Client
 	if err := ctx.ShouldBindJSON(&client); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
 		return
 	}
+	current, ok := c.find(ctx, id)
+	if !ok {
+		return
+	}
+	c.save(ctx, current.ID, &client) // o ID do caminho pode ser alias de um cliente mesclado
+}
+
+// PatchClient godoc
+// @Summary      Altera campos de um cliente
+// @Description  Aplica um JSON Merge Patch (RFC 7396): só os campos enviados mudam, null limpa o campo e address_parts é mesclado campo a campo.
+// @Description  Ex.: {"email": null, "address_parts": {"number": "120"}}. Alterar document recalcula person_type e cnpj, a menos que venham no patch;
+// @Description  alterar address (texto) recalcula address_parts e vice-versa. Retorna o cliente como foi gravado.
+// @Tags         clients
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Param        patch body object true "Campos a alterar"
+// @Success      200 {object} models.Client
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Documento ou e-mail já usado por outro cliente: {error, field, client_id}"
+// @Failure      415 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id} [patch]
+// @Security     ApiKeyAuth
+func (c *ClientCRUDController) Patch(ctx *gin.Context) {
+	id, ok := clientIDParam(ctx)
+	if !ok {
+		return
+	}
+	if ct := ctx.ContentType(); ct != "application/merge-patch+json" && ct != "application/json" {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Use Content-Type application/merge-patch+json"})
+		return
+	}
+	var patch map[string]interface{}
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&patch); err != nil || patch == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: o patch deve ser um objeto"})
+		return
+	}
+	current, ok := c.find(ctx, id)
+	if !ok {
+		return
+	}
+	client, err := patchClient(current, patch)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido: " + err.Error()})
+		return
+	}
+	c.save(ctx, current.ID, &client) // o ID do caminho pode ser alias de um cliente mesclado
+}
+
+// patchClient aplica o merge patch ao cliente. Campos derivados de outro campo alterado pelo patch são limpos
+// para serem recalculados na validação, senão o valor antigo prevaleceria sobre o novo.
+func patchClient(current models.Client, patch map[string]interface{}) (models.Client, error) {
+	var doc interface{}
+	data, err := json.Marshal(current)
+	if err == nil {
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return models.Client{}, err
+	}
+	if data, err = json.Marshal(utils.MergePatch(doc, patch)); err != nil {
+		return models.Client{}, err
+	}
+	var client models.Client
+	if err := json.Unmarshal(data, &client); err != nil {
+		return models.Client{}, err
+	}
+	has := func(field string) bool { _, ok := patch[field]; return ok }
+	if has("document") && !has("cnpj") {
+		client.CNPJ = ""
+	}
+	if has("cnpj") && !has("document") {
+		client.Document = "" // cnpj (legado) informado sozinho passa a ser o documento
+	}
+	if (has("document") || has("cnpj")) && !has("person_type") {
+		client.PersonType = ""
+	}
+	if has("address") && !has("address_parts") {
+		client.AddressParts = models.Address{}
+	}
+	if has("address_parts") && !has("address") {
+		client.Address = ""
+	}
+	return client, nil
+}
+
+// save valida e grava por inteiro o cliente do caminho e responde com o cliente gravado
+func (c *ClientCRUDController) save(ctx *gin.Context, id string, client *models.Client) {
 	client.ID = id
-	if err := c.repo.Update(&client); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar cliente"})
+	client.ImportJobID = "" // preenchido somente pela importação
+	client.Tags = nil       // alteradas somente em POST /clients/tags
+	if errs := client.Validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
 		return
 	}
-	ctx.JSON(http.StatusOK, client)
+	if err := c.repo.WithAudit(requestAudit(ctx)).Update(client); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "record not found"):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+		case respondClientConflict(ctx, err):
+		default:
+			fmt.Printf("[ERRO] Falha ao atualizar cliente %s: %v\n", id, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar cliente"})
+		}
+		return
+	}
+	if saved, ok := c.find(ctx, id); ok {
+		ctx.JSON(http.StatusOK, saved)
+	}
+}
+
+// clientIDParam lê o ID do cliente do caminho. Em caso de erro já escreve a resposta e retorna ok=false.
+func clientIDParam(ctx *gin.Context) (string, bool) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return "", false
+	}
+	return id, true
+}
+
+// find carrega o cliente com as tags. Em caso de erro já escreve a resposta e retorna ok=false.
+func (c *ClientCRUDController) find(ctx *gin.Context, id string) (models.Client, bool) {
+	client, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cliente"})
+		}
+		return models.Client{}, false
+	}
+	clients := []models.Client{client}
+	if err := c.repo.LoadTags(clients); err != nil {
+		fmt.Printf("[ERRO] Falha ao buscar tags do cliente %s: %v\n", client.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cliente"})
+		return models.Client{}, false
+	}
+	return clients[0], true
 }
 
 // DeleteClient godoc
@@ -120,12 +340,54 @@ func (c *ClientCRUDController) Update(ctx *gin.Context) {
 // @Param        id path string true "ID do cliente"
 // @Success      200 {object} map[string]string
 // @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
 // @Router       /clients/{id} [delete]
+// @Security     ApiKeyAuth
 func (c *ClientCRUDController) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
-	if err := c.repo.Delete(id); err != nil {
+	if err := c.repo.WithAudit(requestAudit(ctx)).Delete(id); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar cliente"})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Cliente deletado com sucesso"})
 }
+
+// clientFilterFromQuery monta o filtro de clientes a partir da query string
+func clientFilterFromQuery(ctx *gin.Context) repositories.ClientFilter {
+	return clientFilterFromValues(ctx.Request.URL.Query())
+}
+
+// clientFilterFromValues monta o filtro de clientes a partir de parâmetros já lidos (ex.: de um job de exportação)
+func clientFilterFromValues(q url.Values) repositories.ClientFilter {
+	var filter repositories.ClientFilter
+	if phone := q.Get("phone"); phone != "" {
+		if p, err := utils.ParsePhoneBR(phone); err == nil {
+			filter.PhoneE164 = p.E164()
+		} else {
+			filter.PhoneDigits = utils.OnlyDigits(phone)
+		}
+	}
+	filter.City = strings.TrimSpace(q.Get("city"))
+	filter.UF = strings.ToUpper(strings.TrimSpace(q.Get("uf")))
+	filter.CEP = utils.OnlyDigits(q.Get("cep"))
+	filter.ImportJobID = strings.TrimSpace(q.Get("importJobId"))
+	filter.Tags = tagParams(q["tag"])
+	filter.WithoutTags = tagParams(q["withoutTag"])
+	return filter
+}
+
+// clientFilterParams são os parâmetros de filtro lidos por clientFilterFromValues, além dos campos de ClientListSpec
+var clientFilterParams = []string{"phone", "city", "uf", "cep", "importJobId", "tag", "withoutTag"}
+
+// tagParams junta os nomes de tag de um parâmetro repetido (?tag=a&tag=b) ou separado por vírgulas (?tag=a,b)
+func tagParams(values []string) []string {
+	var tags []string
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			if name = models.NormalizeTagName(name); name != "" {
+				tags = append(tags, name)
+			}
+		}
+	}
+	return tags
+}
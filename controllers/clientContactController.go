@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ClientContactController struct {
+	repo *repositories.ClientRepository
+}
+
+func NewClientContactController(repo *repositories.ClientRepository) *ClientContactController {
+	return &ClientContactController{repo: repo}
+}
+
+// GetAll godoc
+// @Summary      Lista os contatos do cliente
+// @Description  Retorna os contatos (financeiro, comercial, técnico...) do cliente, o principal primeiro
+// @Tags         clients
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Success      200 {array} models.ClientContact
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/contacts [get]
+// @Security     ApiKeyAuth
+func (c *ClientContactController) GetAll(ctx *gin.Context) {
+	client, ok := c.findClient(ctx)
+	if !ok {
+		return
+	}
+	contacts, err := c.repo.Contacts(client.ID)
+	if err != nil {
+		fmt.Printf("[ERRO] Falha ao buscar contatos do cliente %s: %v\n", client.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar contatos"})
+		return
+	}
+	if contacts == nil {
+		contacts = []models.ClientContact{}
+	}
+	ctx.JSON(http.StatusOK, contacts)
+}
+
+// GetByID godoc
+// @Summary      Busca um contato do cliente
+// @Tags         clients
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Param        contactId path string true "ID do contato"
+// @Success      200 {object} models.ClientContact
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/contacts/{contactId} [get]
+// @Security     ApiKeyAuth
+func (c *ClientContactController) GetByID(ctx *gin.Context) {
+	client, ok := c.findClient(ctx)
+	if !ok {
+		return
+	}
+	contact, ok := c.findContact(ctx, client.ID)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, contact)
+}
+
+// Create godoc
+// @Summary      Cria um contato do cliente
+// @Description  O contato precisa de email ou telefone. Tipos: billing, commercial, technical ou other (também aceitos em português: financeiro, comercial, técnico, outro).
+// @Description  Com primary=true o contato passa a ser o principal: os demais deixam de ser e o email e o telefone do cliente passam a ser os dele.
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Param        contact body models.ClientContact true "Dados do contato"
+// @Success      201 {object} models.ClientContact
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "E-mail do contato principal já usado por outro cliente: {error, field, client_id}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/contacts [post]
+// @Security     ApiKeyAuth
+func (c *ClientContactController) Create(ctx *gin.Context) {
+	client, ok := c.findClient(ctx)
+	if !ok {
+		return
+	}
+	var contact models.ClientContact
+	if err := ctx.ShouldBindJSON(&contact); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	contact.ID, contact.ClientID, contact.ImportJobID = uuid.New().String(), client.ID, ""
+	c.save(ctx, &contact, http.StatusCreated)
+}
+
+// Update godoc
+// @Summary      Atualiza um contato do cliente
+// @Description  Substitui os dados do contato. Com primary=true ele passa a ser o principal e o email e o telefone do cliente são atualizados.
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Param        contactId path string true "ID do contato"
+// @Param        contact body models.ClientContact true "Dados do contato"
+// @Success      200 {object} models.ClientContact
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "E-mail do contato principal já usado por outro cliente: {error, field, client_id}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/contacts/{contactId} [put]
+// @Security     ApiKeyAuth
+func (c *ClientContactController) Update(ctx *gin.Context) {
+	client, ok := c.findClient(ctx)
+	if !ok {
+		return
+	}
+	current, ok := c.findContact(ctx, client.ID)
+	if !ok {
+		return
+	}
+	var contact models.ClientContact
+	if err := ctx.ShouldBindJSON(&contact); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	contact.ID, contact.ClientID, contact.ImportJobID, contact.CreatedAt = current.ID, current.ClientID, current.ImportJobID, current.CreatedAt
+	c.save(ctx, &contact, http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Exclui um contato do cliente
+// @Description  O email e o telefone do cliente são mantidos, mesmo que o contato fosse o principal
+// @Tags         clients
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Param        contactId path string true "ID do contato"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/contacts/{contactId} [delete]
+// @Security     ApiKeyAuth
+func (c *ClientContactController) Delete(ctx *gin.Context) {
+	client, ok := c.findClient(ctx)
+	if !ok {
+		return
+	}
+	contactID := ctx.Param("contactId")
+	if _, err := uuid.Parse(contactID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do contato inválido. Use um UUID válido."})
+		return
+	}
+	if err := c.repo.DeleteContact(client.ID, contactID); err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Contato não encontrado"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir contato"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Contato excluído com sucesso"})
+}
+
+// save valida e grava o contato, respondendo com status em caso de sucesso
+func (c *ClientContactController) save(ctx *gin.Context, contact *models.ClientContact, status int) {
+	if errs := contact.Validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
+		return
+	}
+	if err := c.repo.WithAudit(requestAudit(ctx)).SaveContact(contact); err != nil {
+		// Um contato principal leva o e-mail ao cliente, que pode já ser de outro cliente
+		if !respondClientConflict(ctx, err) {
+			fmt.Printf("[ERRO] Falha ao gravar contato do cliente %s: %v\n", contact.ClientID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gravar contato"})
+		}
+		return
+	}
+	ctx.JSON(status, contact)
+}
+
+// findClient carrega o cliente do caminho. Em caso de erro já escreve a resposta e retorna ok=false.
+func (c *ClientContactController) findClient(ctx *gin.Context) (models.Client, bool) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return models.Client{}, false
+	}
+	client, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cliente"})
+		}
+		return models.Client{}, false
+	}
+	return client, true
+}
+
+// findContact carrega o contato do caminho, que deve pertencer ao cliente. Em caso de erro já escreve a resposta.
+func (c *ClientContactController) findContact(ctx *gin.Context, clientID string) (models.ClientContact, bool) {
+	contactID := ctx.Param("contactId")
+	if _, err := uuid.Parse(contactID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do contato inválido. Use um UUID válido."})
+		return models.ClientContact{}, false
+	}
+	contact, err := c.repo.GetContact(clientID, contactID)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Contato não encontrado"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar contato"})
+		}
+		return models.ClientContact{}, false
+	}
+	return contact, true
+}
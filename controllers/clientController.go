@@ -1,210 +1,676 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	middlewares "minha-api/middleware"
 	"minha-api/models"
 	"minha-api/repositories"
+	"minha-api/utils"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
-	"github.com/extrame/xls"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/xuri/excelize/v2"
 )
 
 type ClientController struct {
-	repo *repositories.ClientRepository
+	repo         *repositories.ClientRepository
+	templateRepo *repositories.ImportTemplateRepository
+	jobRepo      *repositories.ImportJobRepository
+	registry     utils.CompanyRegistry // usado pela importação com enrich=true
+	previews     *importPreviewStore
 }
 
-func NewClientController(repo *repositories.ClientRepository) *ClientController {
-	return &ClientController{repo: repo}
+func NewClientController(repo *repositories.ClientRepository, templateRepo *repositories.ImportTemplateRepository, jobRepo *repositories.ImportJobRepository, registry utils.CompanyRegistry) *ClientController {
+	return &ClientController{repo: repo, templateRepo: templateRepo, jobRepo: jobRepo, registry: registry, previews: newImportPreviewStore(importPreviewTTL(),
+		importPreviewLimit("IMPORT_PREVIEW_MAX", importPreviewDefaultMax), importPreviewLimit("IMPORT_PREVIEW_MAX_ROWS", importPreviewDefaultMaxRows))}
+}
+
+// clientImportFields lista os campos de models.Client que podem ser preenchidos pela importação
+var clientImportFields = map[string]bool{
+	"name":        true,
+	"email":       true,
+	"phone":       true,
+	"address":     true,
+	"cnpj":        true, // legado: tratado como documento
+	"document":    true,
+	"person_type": true,
+	// partes do endereço, para planilhas que já trazem o endereço em colunas separadas
+	"street":       true,
+	"number":       true,
+	"complement":   true,
+	"neighborhood": true,
+	"city":         true,
+	"uf":           true,
+	"cep":          true,
+	// contato da linha (ver models.ClientContact); linhas repetidas do mesmo cliente acrescentam contatos
+	"contact_type":  true,
+	"contact_name":  true,
+	"contact_email": true,
+	"contact_phone": true,
+}
+
+// headerAliases mapeia cabeçalhos normalizados (ver normalizeHeader) para campos de models.Client
+var headerAliases = map[string]string{
+	"nome":              "name",
+	"name":              "name",
+	"razaosocial":       "name",
+	"cliente":           "name",
+	"email":             "email",
+	"correioeletronico": "email",
+	"telefone":          "phone",
+	"tel":               "phone",
+	"celular":           "phone",
+	"fone":              "phone",
+	"phone":             "phone",
+	"endereco":          "address",
+	"address":           "address",
+	"cnpj":              "document",
+	"cpf":               "document",
+	"cpfcnpj":           "document",
+	"cnpjcpf":           "document",
+	"documento":         "document",
+	"tipopessoa":        "person_type",
+	"tipodepessoa":      "person_type",
+	"logradouro":        "street",
+	"rua":               "street",
+	"numero":            "number",
+	"num":               "number",
+	"complemento":       "complement",
+	"bairro":            "neighborhood",
+	"cidade":            "city",
+	"municipio":         "city",
+	"uf":                "uf",
+	"estado":            "uf",
+	"cep":               "cep",
+	"tipocontato":       "contact_type",
+	"tipodecontato":     "contact_type",
+	"contato":           "contact_name",
+	"nomecontato":       "contact_name",
+	"nomedocontato":     "contact_name",
+	"emailcontato":      "contact_email",
+	"emaildocontato":    "contact_email",
+	"telefonecontato":   "contact_phone",
+	"telefonedocontato": "contact_phone",
+	"celularcontato":    "contact_phone",
 }
 
 // UploadClients godoc
 // @Summary      Upload de clientes via arquivo Excel
-// @Description  Recebe um arquivo .xls, lê os dados e cadastra clientes no banco
+// @Description  Recebe uma planilha (.xlsx, .xls, .ods, .csv ou .tsv), lê os dados e cadastra clientes no banco.
+// @Description  Em CSV/TSV a codificação (UTF-8, UTF-16 ou Windows-1252) e o separador (; , tab |) são detectados automaticamente.
+// @Description  Com dryRun=true nada é gravado: a resposta traz o resultado previsto de cada linha e um preview_token
+// @Description  que pode ser enviado depois (sem o arquivo) para efetivar a mesma importação, em até 30 minutos (IMPORT_PREVIEW_TTL).
+// @Description  Arquivos com mais de 100.000 linhas (IMPORT_PREVIEW_MAX_ROWS) não recebem preview_token; só as 20 pré-visualizações mais
+// @Description  recentes (IMPORT_PREVIEW_MAX) são guardadas, em memória: os tokens se perdem quando a API reinicia.
+// @Description  Colunas de contato (Tipo Contato, Nome Contato, Email Contato, Telefone Contato) são opcionais: com elas a planilha pode trazer um contato por linha,
+// @Description  repetindo os dados do cliente (ex.: agrupadas pelo CNPJ). As linhas repetidas acrescentam o contato ao cliente da primeira linha (status contact_added).
+// @Description  Contatos importados não são principais: o email e o telefone do cliente continuam vindo das colunas do cliente.
 // @Tags         clients
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        file formData file true "Arquivo de clientes (.xls)"
-// @Success      201 {object} map[string]interface{}
+// @Param        file formData file false "Arquivo de clientes (.xlsx, .xls, .ods, .csv ou .tsv). Obrigatório quando previewToken não é informado"
+// @Param        templateId query string false "ID de um template de importação para mapear as colunas"
+// @Param        dryRun query bool false "Apenas valida e simula a importação, sem gravar"
+// @Param        previewToken query string false "Token de uma pré-visualização (dryRun) a ser efetivada"
+// @Param        sheet query string false "Aba a importar, pelo nome ou pela posição (1 = primeira aba). Padrão: primeira aba"
+// @Param        allSheets query bool false "Importa todas as abas; abas sem o cabeçalho esperado são reportadas e ignoradas"
+// @Param        headerRow query int false "Linha do cabeçalho (padrão 1), para planilhas com títulos acima dele"
+// @Param        maxRows query int false "Limite de linhas do arquivo; pode reduzir, mas não aumentar, o limite configurado em IMPORT_MAX_ROWS (padrão 1.000.000)"
+// @Param        atomic query string false "true: grava tudo em uma transação e desfaz a importação inteira se alguma linha falhar; savepoint: pula as linhas com problema e confirma o restante de uma vez" Enums(true, savepoint)
+// @Param        mode query string false "insert-only (padrão): ignora clientes existentes; upsert: atualiza os campos preenchidos que mudaram; replace: substitui todos os campos" Enums(insert-only, upsert, replace)
+// @Param        enrich query bool false "Completa os campos vazios das linhas com CNPJ (razão social, email, telefone, endereço) pelo cadastro de empresas. Linhas em que a consulta falha são importadas como estão, com enrich_error"
+// @Param        matchKey query string false "Chave para encontrar o cliente existente. Padrão: nome+documento, ou nome+email quando não há documento" Enums(document, email, name+email)
+// @Success      200 {object} map[string]interface{} "Resultado do dry-run"
+// @Success      201 {object} map[string]interface{} "Totais da importação e import_job_id, usado para consultar ou desfazer a importação em /imports"
 // @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
 // @Router       /clients/upload [post]
+// @Security     ApiKeyAuth
 func (c *ClientController) UploadClients(ctx *gin.Context) {
+	opts := importOptions{
+		DryRun:   importParam(ctx, "dryRun") == "true",
+		Mode:     importParam(ctx, "mode"),
+		MatchKey: importParam(ctx, "matchKey"),
+		Atomic:   importParam(ctx, "atomic"),
+		Enrich:   importParam(ctx, "enrich") == "true",
+	}
+	if err := opts.validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	maxRows, err := importMaxRows(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var data *importData
+	var feed importFeed
+	if token := importParam(ctx, "previewToken"); token != "" {
+		preview, previewOpts, ok := c.previews.Take(token)
+		if !ok {
+			fmt.Println("[ERRO] Token de pré-visualização inválido ou expirado:", token)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Token de pré-visualização inválido ou expirado"})
+			return
+		}
+		// Efetiva exatamente o que foi pré-visualizado
+		opts.Mode, opts.MatchKey, opts.Enrich = previewOpts.Mode, previewOpts.MatchKey, previewOpts.Enrich
+		fmt.Printf("[INFO] Efetivando pré-visualização %s (%d linhas)\n", token, len(preview.Rows))
+		data = &preview
+		feed = func(out chan<- importRow) error {
+			defer close(out)
+			for _, row := range preview.Rows {
+				out <- row
+			}
+			return nil
+		}
+	} else {
+		src, ok := c.openImportSource(ctx)
+		if !ok {
+			return
+		}
+		defer src.Close()
+		data = &src.data
+		feed = func(out chan<- importRow) error {
+			keep := 0
+			if opts.DryRun {
+				keep = c.previews.maxRows // a pré-visualização precisa guardar as linhas
+			}
+			return src.stream(out, maxRows, keep)
+		}
+	}
+
+	var job *models.ImportJob
+	if !opts.DryRun {
+		job = &models.ImportJob{FileName: data.FileName, CreatedBy: importActor(ctx), Options: data.Params, Status: models.ImportJobProcessing}
+		job.Options["mode"], job.Options["matchKey"], job.Options["atomic"] = opts.Mode, opts.MatchKey, opts.Atomic
+		if opts.Enrich {
+			job.Options["enrich"] = "true"
+		}
+		if err := c.jobRepo.Create(job); err != nil {
+			fmt.Println("[ERRO] Falha ao registrar job de importação:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar a importação"})
+			return
+		}
+		opts.JobID = job.ID
+	}
+
+	results := newImportCollector(opts.DryRun)
+	if opts.Atomic != "" && !opts.DryRun {
+		if !c.runAtomicImport(ctx, job, data, feed, opts, results) {
+			return
+		}
+	} else if err := c.runImport(c.repo.WithAudit(requestAudit(ctx)), feed, opts, results.add); err != nil {
+		fmt.Println("[ERRO] Importação interrompida:", err)
+		if job != nil {
+			c.finishImportJob(job, models.ImportJobInterrupted, results.Totals)
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":         importLimitMessage(maxRows, job),
+			"import_job_id": jobID(job),
+			"totals":        results.Totals,
+			"sheets":        results.sheets(data.Sheets),
+		})
+		return
+	}
+	sheets := results.sheets(data.Sheets)
+
+	if opts.DryRun && data.Partial {
+		ctx.JSON(http.StatusOK, gin.H{
+			"dry_run":   true,
+			"mode":      opts.Mode,
+			"match_key": opts.MatchKey,
+			"warning":   fmt.Sprintf("O arquivo passa de %d linhas e a pré-visualização não pode ser efetivada por token: envie-o novamente sem dryRun", c.previews.maxRows),
+			"totals":    results.Totals,
+			"sheets":    sheets,
+			"rows":      results.sortedRows(),
+		})
+		return
+	}
+	if opts.DryRun {
+		token, expiresAt := c.previews.Save(*data, opts)
+		ctx.JSON(http.StatusOK, gin.H{
+			"dry_run":       true,
+			"mode":          opts.Mode,
+			"match_key":     opts.MatchKey,
+			"preview_token": token,
+			"expires_at":    expiresAt,
+			"totals":        results.Totals,
+			"sheets":        sheets,
+			"rows":          results.sortedRows(),
+		})
+		return
+	}
+
+	totals := results.Totals
+	c.finishImportJob(job, models.ImportJobCompleted, totals)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"import_job_id":             job.ID,
+		"clientes importados":       totals[importStatusInserted],
+		"atualizados":               totals[importStatusUpdated],
+		"inalterados":               totals[importStatusUnchanged],
+		"ignorados por duplicidade": totals[importStatusDuplicate],
+		"invalidos":                 totals[importStatusInvalid],
+		"erros de banco":            totals[importStatusError],
+		"contatos adicionados":      totals[importStatusContactAdded],
+		"atomic":                    opts.Atomic,
+		"sheets":                    sheets,
+	})
+}
+
+// importFeed envia as linhas a importar para out e fecha out ao terminar
+type importFeed func(out chan<- importRow) error
+
+// runImport lê as linhas em uma goroutine e as processa à medida que chegam. O canal limita as linhas em
+// trânsito entre leitura e gravação a um lote, de modo que a memória não cresce com o tamanho do arquivo.
+func (c *ClientController) runImport(repo *repositories.ClientRepository, feed importFeed, opts importOptions, emit func(importRowResult)) error {
+	rows := make(chan importRow, importBatchSize)
+	errc := make(chan error, 1)
+	go func() { errc <- feed(rows) }()
+	c.processImport(repo, rows, opts, emit)
+	return <-errc
+}
+
+// errImportAborted cancela a transação de uma importação tudo ou nada
+var errImportAborted = errors.New("importação cancelada")
+
+// runAtomicImport processa as linhas em uma única transação. Com atomic=true qualquer linha inválida ou com
+// erro desfaz tudo e a resposta traz a lista completa de problemas; com atomic=savepoint essas linhas são
+// puladas e o restante é confirmado de uma vez no fim. Um arquivo acima do limite de linhas também desfaz tudo.
+// Em caso de erro marca o job como cancelado, já escreve a resposta e retorna false.
+func (c *ClientController) runAtomicImport(ctx *gin.Context, job *models.ImportJob, data *importData, feed importFeed, opts importOptions, results *importCollector) bool {
+	err := c.repo.WithAudit(requestAudit(ctx)).Transaction(func(tx *repositories.ClientRepository) error {
+		if err := c.runImport(tx, feed, opts, results.add); err != nil {
+			return err
+		}
+		if opts.Atomic == importAtomicAll && results.failed() {
+			return errImportAborted
+		}
+		return nil
+	})
+	if errors.Is(err, errImportAborted) || errors.Is(err, errImportRowLimit) {
+		results.rollBack()
+		message := "Importação cancelada: nenhuma alteração foi gravada porque há linhas com problema"
+		if errors.Is(err, errImportRowLimit) {
+			message = "Importação cancelada: " + err.Error() + ". Nenhuma alteração foi gravada"
+		}
+		fmt.Printf("[INFO] Importação atômica cancelada: %d linha(s) com problema\n", results.Totals[importStatusInvalid]+results.Totals[importStatusError])
+		c.finishImportJob(job, models.ImportJobCancelled, results.Totals)
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":            message,
+			"import_job_id":    job.ID,
+			"atomic":           opts.Atomic,
+			"totals":           results.Totals,
+			"sheets":           results.sheets(data.Sheets),
+			"errors":           results.Failures,
+			"errors_truncated": results.FailuresTruncated,
+		})
+		return false
+	}
+	if err != nil {
+		fmt.Println("[ERRO] Falha ao confirmar a transação da importação:", err)
+		c.finishImportJob(job, models.ImportJobCancelled, nil)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gravar a importação: nenhuma alteração foi gravada"})
+		return false
+	}
+	return true
+}
+
+// finishImportJob grava a situação final e os totais do job. Falhas só são registradas no log:
+// a importação em si já terminou.
+func (c *ClientController) finishImportJob(job *models.ImportJob, status string, totals map[string]int) {
+	now := time.Now()
+	job.Status, job.Totals, job.FinishedAt = status, totals, &now
+	if err := c.jobRepo.Update(job); err != nil {
+		fmt.Printf("[ERRO] Falha ao atualizar job de importação %s: %v\n", job.ID, err)
+	}
+}
+
+// importActor identifica quem fez a importação: o usuário da API key usada
+func importActor(ctx *gin.Context) string {
+	return middlewares.Actor(ctx)
+}
+
+// requestAudit identifica o autor e a requisição das alterações de clientes, para o histórico
+func requestAudit(ctx *gin.Context) repositories.Audit {
+	return repositories.Audit{Actor: middlewares.Actor(ctx), RequestID: middlewares.RequestID(ctx)}
+}
+
+// defaultImportMaxRows é o limite de linhas por importação quando IMPORT_MAX_ROWS não está definido
+const defaultImportMaxRows = 1000000
+
+// errImportRowLimit interrompe a leitura de um arquivo com mais linhas que o permitido
+var errImportRowLimit = errors.New("o arquivo passa do limite de linhas")
+
+// importMaxRows retorna o limite de linhas da importação: IMPORT_MAX_ROWS (ou o padrão), que o parâmetro
+// maxRows pode reduzir mas não aumentar
+func importMaxRows(ctx *gin.Context) (int, error) {
+	limit := defaultImportMaxRows
+	if v := os.Getenv("IMPORT_MAX_ROWS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if v := importParam(ctx, "maxRows"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("maxRows deve ser um número inteiro positivo")
+		}
+		limit = min(n, limit)
+	}
+	return limit, nil
+}
+
+// importLimitMessage descreve a interrupção de uma importação não atômica pelo limite de linhas
+func importLimitMessage(maxRows int, job *models.ImportJob) string {
+	if job == nil {
+		return fmt.Sprintf("O arquivo passa do limite de %d linhas", maxRows)
+	}
+	return fmt.Sprintf("Importação interrompida: o arquivo passa do limite de %d linhas. As linhas anteriores ao limite foram gravadas; use POST /imports/%s/rollback para desfazê-las", maxRows, job.ID)
+}
+
+func jobID(job *models.ImportJob) string {
+	if job == nil {
+		return ""
+	}
+	return job.ID
+}
+
+// importSheetReader é uma aba selecionada, com o iterador já posicionado depois do cabeçalho
+type importSheetReader struct {
+	sheet  int // índice em importData.Sheets
+	rows   utils.RowIterator
+	colMap map[string]int
+	line   int // última linha lida, como aparece no Excel
+}
+
+// importSource é o arquivo enviado, aberto para leitura sob demanda
+type importSource struct {
+	tempPath string
+	book     utils.Spreadsheet
+	readers  []*importSheetReader
+	data     importData // Rows só é preenchido por stream com keep=true
+}
+
+// Close fecha os iteradores e a planilha e remove o arquivo temporário
+func (s *importSource) Close() {
+	for _, r := range s.readers {
+		r.rows.Close()
+	}
+	s.book.Close()
+	os.Remove(s.tempPath)
+}
+
+// openImportSource abre o arquivo enviado e lê o cabeçalho das abas selecionadas; os dados são lidos
+// depois, por stream. Em caso de erro já escreve a resposta e retorna ok=false.
+func (c *ClientController) openImportSource(ctx *gin.Context) (*importSource, bool) {
 	file, err := ctx.FormFile("file")
 	if err != nil {
 		fmt.Println("[ERRO] Arquivo não enviado:", err)
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo não enviado"})
-		return
+		return nil, false
 	}
 	fmt.Println("Arquivo recebido:", file.Filename, file.Size)
 
 	// Verifica extensão
 	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext != ".xls" && ext != ".xlsx" {
+	if !slices.Contains(utils.SpreadsheetExtensions, ext) {
 		fmt.Println("[ERRO] Extensão inválida:", ext)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O arquivo deve ser .xls ou .xlsx"})
-		return
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O arquivo deve ser " + strings.Join(utils.SpreadsheetExtensions, ", ")})
+		return nil, false
+	}
+
+	headerRow := 1
+	if v := importParam(ctx, "headerRow"); v != "" {
+		headerRow, err = strconv.Atoi(v)
+		if err != nil || headerRow < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "headerRow deve ser o número da linha do cabeçalho (1 ou mais)"})
+			return nil, false
+		}
+	}
+
+	var template *models.ImportTemplate
+	if templateID := importParam(ctx, "templateId"); templateID != "" {
+		if _, err := uuid.Parse(templateID); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID de template inválido. Use um UUID válido."})
+			return nil, false
+		}
+		t, err := c.templateRepo.GetByID(templateID)
+		if err != nil {
+			fmt.Println("[ERRO] Template de importação não encontrado:", templateID, err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Template de importação não encontrado"})
+			return nil, false
+		}
+		template = &t
 	}
 
 	tempPath := "/tmp/" + uuid.New().String() + ext
 	if err := ctx.SaveUploadedFile(file, tempPath); err != nil {
 		fmt.Println("[ERRO] Erro ao salvar arquivo temporário:", err)
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao salvar arquivo temporário"})
-		return
+		return nil, false
 	}
-	defer os.Remove(tempPath)
 
-	var rows [][]string
-	if ext == ".xlsx" {
-		xl, err := excelize.OpenFile(tempPath)
-		if err != nil {
-			fmt.Println("[ERRO] Erro ao abrir arquivo Excel:", err)
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao abrir arquivo Excel"})
-			return
-		}
-		sheet := xl.GetSheetName(0)
-		rows, err = xl.GetRows(sheet)
-		if err != nil {
-			fmt.Println("[ERRO] Erro ao ler linhas do Excel:", err)
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler linhas do Excel"})
-			return
+	book, err := utils.OpenSpreadsheet(tempPath, ext)
+	if err != nil {
+		os.Remove(tempPath)
+		fmt.Println("[ERRO] Erro ao abrir planilha:", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao abrir planilha"})
+		return nil, false
+	}
+	src := &importSource{tempPath: tempPath, book: book}
+	src.data = importData{FileName: file.Filename, Params: map[string]string{}}
+	for _, name := range []string{"templateId", "sheet", "allSheets", "headerRow"} {
+		if v := importParam(ctx, name); v != "" {
+			src.data.Params[name] = v
 		}
-	} else {
-		xlsFile, err := xls.Open(tempPath, "utf-8")
+	}
+
+	names := book.SheetNames()
+	selected, err := utils.SelectSheets(names, importParam(ctx, "sheet"), importParam(ctx, "allSheets") == "true")
+	if err != nil {
+		src.Close()
+		fmt.Println("[ERRO] Seleção de aba inválida:", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Aba inválida: " + err.Error()})
+		return nil, false
+	}
+
+	for _, idx := range selected {
+		sheet := importSheet{Name: names[idx]}
+		reader, err := openSheetReader(book, idx, headerRow, template)
 		if err != nil {
-			fmt.Println("[ERRO] Erro ao abrir arquivo XLS:", err)
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao abrir arquivo XLS"})
-			return
-		}
-		sheet := xlsFile.GetSheet(0)
-		if sheet == nil {
-			fmt.Println("[ERRO] Não foi possível ler a primeira planilha do XLS")
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Não foi possível ler a primeira planilha do XLS"})
-			return
-		}
-		for i := 0; i <= int(sheet.MaxRow); i++ {
-			row := sheet.Row(i)
-			var rowData []string
-			for j := 0; j < row.LastCol(); j++ {
-				rowData = append(rowData, row.Col(j))
-			}
-			rows = append(rows, rowData)
+			fmt.Printf("[ERRO] Aba '%s' ignorada: %v\n", sheet.Name, err)
+			sheet.Error = err.Error()
+		} else {
+			reader.sheet = len(src.data.Sheets)
+			src.readers = append(src.readers, reader)
 		}
+		src.data.Sheets = append(src.data.Sheets, sheet)
 	}
 
-	if len(rows) == 0 {
-		fmt.Println("[ERRO] Arquivo Excel vazio")
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo Excel vazio"})
-		return
+	// Com uma única aba, o problema dela é o problema do arquivo inteiro
+	if len(src.data.Sheets) == 1 && src.data.Sheets[0].Error != "" {
+		src.Close()
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": src.data.Sheets[0].Error})
+		return nil, false
+	}
+	if len(src.readers) == 0 {
+		src.Close()
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Nenhuma aba com dados válidos para importar", "sheets": src.data.Sheets})
+		return nil, false
 	}
+	return src, true
+}
 
-	header := rows[0]
-	fmt.Println("Header detectado:", header)
-	// Mapeamento flexível dos campos
-	colMap := map[string]int{}
-	for idx, col := range header {
-		normCol := normalizeHeader(col)
-		fmt.Printf("Coluna original: '%s' | Normalizada: '%s'\n", col, normCol)
-		if normCol == "nome" {
-			colMap["nome"] = idx
-		} else if normCol == "email" {
-			colMap["email"] = idx
-		} else if normCol == "telefone" {
-			colMap["telefone"] = idx
-		} else if normCol == "endereco" {
-			colMap["endereco"] = idx
-		}
-	}
-	fmt.Println("Mapeamento de colunas:", colMap)
-	// Verifica se todos os campos obrigatórios existem
-	required := []string{"nome", "email", "telefone", "endereco"}
+// openSheetReader abre a aba e lê até o cabeçalho. headerRow é a linha (a partir de 1) do cabeçalho;
+// as linhas acima dele (títulos, observações) são ignoradas.
+func openSheetReader(book utils.Spreadsheet, sheet, headerRow int, template *models.ImportTemplate) (*importSheetReader, error) {
+	rows, err := book.Rows(sheet)
+	if err != nil {
+		fmt.Printf("[ERRO] Erro ao ler linhas da aba %d: %v\n", sheet+1, err)
+		return nil, fmt.Errorf("Erro ao ler linhas da planilha")
+	}
+	reader := &importSheetReader{rows: rows}
+	for reader.line < headerRow && rows.Next() {
+		reader.line++
+	}
+	if reader.line < headerRow {
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			fmt.Printf("[ERRO] Erro ao ler linhas da aba %d: %v\n", sheet+1, err)
+			return nil, fmt.Errorf("Erro ao ler linhas da planilha")
+		}
+		return nil, fmt.Errorf("Arquivo Excel vazio")
+	}
+	header := rows.Row()
+	fmt.Printf("Header detectado na aba %d: %v\n", sheet+1, header)
+	reader.colMap = buildColumnMap(header, template)
+	fmt.Println("Mapeamento de colunas:", reader.colMap)
+	// Verifica se todos os campos obrigatórios existem. O endereço pode vir em uma coluna só ou separado em partes.
+	if _, ok := reader.colMap["address"]; !ok {
+		if _, ok := reader.colMap["street"]; ok {
+			reader.colMap["address"] = reader.colMap["street"]
+		}
+	}
+	required := []string{"name", "email", "phone", "address"}
 	for _, req := range required {
-		if _, ok := colMap[req]; !ok {
+		if _, ok := reader.colMap[req]; !ok {
+			rows.Close()
 			fmt.Println("[ERRO] Cabeçalho faltando campo obrigatório:", req)
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cabeçalho do arquivo deve conter as colunas: Nome, Email, Telefone, Endereço (em qualquer ordem)"})
-			return
+			return nil, fmt.Errorf("Cabeçalho do arquivo deve conter as colunas: Nome, Email, Telefone, Endereço (em qualquer ordem)")
 		}
 	}
+	return reader, nil
+}
 
-	var count, ignored, dbErrors int
-	for i, row := range rows {
-		if i == 0 {
-			continue // pula header
-		}
-		// Pega os valores pelas posições mapeadas
-		name := ""
-		email := ""
-		phone := ""
-		address := ""
-		cnpj := ""
-		if colMap["nome"] < len(row) {
-			name = row[colMap["nome"]]
-		}
-		if colMap["email"] < len(row) {
-			email = row[colMap["email"]]
-		}
-		if colMap["telefone"] < len(row) {
-			phone = row[colMap["telefone"]]
-		}
-		if colMap["endereco"] < len(row) {
-			address = row[colMap["endereco"]]
-		}
-		// Tenta pegar CNPJ se existir na planilha
-		for idx, col := range header {
-			if normalizeHeader(col) == "cnpj" && idx < len(row) {
-				cnpj = row[idx]
+// stream lê as abas em sequência e envia cada linha de dados para out, fechando out ao terminar.
+// Linhas totalmente em branco são ignoradas. Ao passar de maxRows linhas a leitura é interrompida com
+// errImportRowLimit. Até keep linhas também são guardadas em data.Rows (pré-visualização); se o arquivo tiver
+// mais, as guardadas são descartadas e data.Partial fica true.
+func (s *importSource) stream(out chan<- importRow, maxRows int, keep int) error {
+	defer close(out)
+	count := 0
+	for _, r := range s.readers {
+		sheet := &s.data.Sheets[r.sheet]
+		for r.rows.Next() {
+			r.line++
+			row := r.rows.Row()
+			if strings.TrimSpace(strings.Join(row, "")) == "" {
+				continue
 			}
+			count++
+			if count > maxRows {
+				return fmt.Errorf("%w (%d)", errImportRowLimit, maxRows)
+			}
+			parsed := newImportRow(sheet.Name, r.line, row, r.colMap)
+			parsed.Seq = count
+			switch {
+			case count <= keep:
+				s.data.Rows = append(s.data.Rows, parsed)
+			case keep > 0 && !s.data.Partial:
+				s.data.Rows, s.data.Partial = nil, true
+			}
+			out <- parsed
 		}
-		fmt.Printf("[DEBUG] Linha %d: nome='%s', cnpj='%s', email='%s', telefone='%s', endereco='%s'\n", i, name, cnpj, email, phone, address)
-		// Validação: não cadastrar cliente duplicado
-		var exists bool
-		if cnpj != "" {
-			exists, err = c.repo.ExistsByNameAndCNPJ(name, cnpj)
-		} else {
-			exists, err = c.repo.ExistsByNameAndEmail(name, email)
+		if err := r.rows.Err(); err != nil {
+			fmt.Printf("[ERRO] Erro ao ler linhas da aba '%s' (linha %d): %v\n", sheet.Name, r.line+1, err)
+			sheet.Error = fmt.Sprintf("Erro ao ler linhas da planilha a partir da linha %d", r.line+1)
 		}
-		if err != nil {
-			if !strings.Contains(err.Error(), "record not found") {
-				fmt.Printf("[ERRO] Falha ao verificar duplicidade (linha %d): %v\n", i, err)
-				dbErrors++
-			}
-			continue
+	}
+	return nil
+}
+
+// newImportRow converte uma linha de dados da planilha em cliente, pelas posições mapeadas no cabeçalho.
+// Se a linha trouxer nome, email ou telefone de contato, o contato também é lido.
+func newImportRow(sheetName string, line int, row []string, colMap map[string]int) importRow {
+	parsed := importRow{
+		Sheet: sheetName,
+		Line:  line,
+		Client: models.Client{
+			Name:       cellValue(row, colMap, "name"),
+			Email:      cellValue(row, colMap, "email"),
+			Phone:      cellValue(row, colMap, "phone"),
+			Address:    cellValue(row, colMap, "address"),
+			Document:   cellValue(row, colMap, "document"), // opcional
+			CNPJ:       cellValue(row, colMap, "cnpj"),     // opcional, vindo de templates antigos
+			PersonType: cellValue(row, colMap, "person_type"),
+			AddressParts: models.Address{
+				Street:       cellValue(row, colMap, "street"),
+				Number:       cellValue(row, colMap, "number"),
+				Complement:   cellValue(row, colMap, "complement"),
+				Neighborhood: cellValue(row, colMap, "neighborhood"),
+				City:         cellValue(row, colMap, "city"),
+				UF:           cellValue(row, colMap, "uf"),
+				CEP:          cellValue(row, colMap, "cep"),
+			},
+		},
+	}
+	contact := models.ClientContact{
+		Type:  cellValue(row, colMap, "contact_type"),
+		Name:  cellValue(row, colMap, "contact_name"),
+		Email: cellValue(row, colMap, "contact_email"),
+		Phone: cellValue(row, colMap, "contact_phone"),
+	}
+	if contact.Name != "" || contact.Email != "" || contact.Phone != "" {
+		parsed.Contact = &contact
+	}
+	return parsed
+}
+
+// buildColumnMap associa cada campo do cliente à posição da coluna no cabeçalho.
+// O template, quando informado, tem prioridade; colunas que ele não cobre caem nos sinônimos embutidos.
+func buildColumnMap(header []string, template *models.ImportTemplate) map[string]int {
+	templateCols := map[string]string{}
+	if template != nil {
+		for src, field := range template.Columns {
+			templateCols[normalizeHeader(src)] = field
 		}
-		if exists {
-			fmt.Printf("[INFO] Cliente duplicado ignorado: nome='%s', cnpj='%s'\n", name, cnpj)
-			ignored++
-			continue
+	}
+	colMap := map[string]int{}
+	for idx, col := range header {
+		normCol := normalizeHeader(col)
+		field, ok := templateCols[normCol]
+		if !ok {
+			field, ok = headerAliases[normCol]
 		}
-		client := models.Client{
-			ID:      uuid.New().String(),
-			Name:    name,
-			Email:   email,
-			Phone:   phone,
-			Address: address,
-			CNPJ:    cnpj,
+		fmt.Printf("Coluna original: '%s' | Normalizada: '%s' | Campo: '%s'\n", col, normCol, field)
+		if !ok {
+			continue
 		}
-		if err := c.repo.Create(&client); err == nil {
-			count++
-		} else {
-			fmt.Printf("[ERRO] Falha ao inserir cliente (linha %d): %v\n", i, err)
-			dbErrors++
+		if _, dup := colMap[field]; !dup {
+			colMap[field] = idx
 		}
 	}
+	return colMap
+}
 
-	ctx.JSON(http.StatusCreated, gin.H{
-		"clientes importados":       count,
-		"ignorados por duplicidade": ignored,
-		"erros de banco":            dbErrors,
-	})
+// cellValue retorna o valor da coluna mapeada para o campo, ou "" se a coluna não existir na linha
+func cellValue(row []string, colMap map[string]int, field string) string {
+	idx, ok := colMap[field]
+	if !ok || idx >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[idx])
+}
+
+// importParam lê um parâmetro da importação pela query string ou, na falta dela, pelo formulário multipart
+func importParam(ctx *gin.Context, name string) string {
+	if v := ctx.Query(name); v != "" {
+		return v
+	}
+	return ctx.PostForm(name)
 }
 
 func normalizeHeader(s string) string {
-	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ToLower(strings.TrimSpace(utils.RemoveAccents(s)))
 	// Remove acentos e caracteres especiais
 	norm := make([]rune, 0, len(s))
 	for _, r := range s {
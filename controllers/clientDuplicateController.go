@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDuplicateMinScore = 0.6
+	defaultDuplicateLimit    = 100
+	// duplicateCandidateBatch é quantos pares candidatos são carregados e avaliados por vez
+	duplicateCandidateBatch = 1000
+)
+
+type ClientDuplicateController struct {
+	repo *repositories.ClientRepository
+}
+
+func NewClientDuplicateController(repo *repositories.ClientRepository) *ClientDuplicateController {
+	return &ClientDuplicateController{repo: repo}
+}
+
+// mergeClientsRequest é o corpo de POST /clients/merge
+type mergeClientsRequest struct {
+	KeepID   string            `json:"keep_id" example:"b3e1c2d0-1234-4abc-9def-1234567890ab"`
+	RemoveID string            `json:"remove_id" example:"c4f2d3e1-2345-4bcd-8ef0-234567890abc"`
+	Fields   map[string]string `json:"fields"` // campo -> "keep" ou "remove"
+}
+
+// FindClientDuplicates godoc
+// @Summary      Lista prováveis clientes duplicados
+// @Description  Compara os clientes por nome (sem acentos, pontuação e sufixos como LTDA, ME, S/A), documento, email e telefone
+// @Description  e retorna os pares com nota a partir de minScore, da maior para a menor nota. Os candidatos são gerados no banco:
+// @Description  clientes com documento, email ou telefone iguais e, com minScore até 0.5, também com nomes parecidos (pg_trgm).
+// @Tags         clients
+// @Produce      json
+// @Param        minScore query number false "Nota mínima, de 0 a 1 (padrão 0.6)"
+// @Param        limit query int false "Máximo de pares retornados (padrão 100)"
+// @Success      200 {array} models.ClientDuplicate
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/duplicates [get]
+// @Security     ApiKeyAuth
+func (c *ClientDuplicateController) Duplicates(ctx *gin.Context) {
+	minScore := defaultDuplicateMinScore
+	if s := ctx.Query("minScore"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || v > 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "minScore deve ser um número entre 0 e 1"})
+			return
+		}
+		minScore = v
+	}
+	limit := defaultDuplicateLimit
+	if s := ctx.Query("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um inteiro positivo"})
+			return
+		}
+		limit = v
+	}
+
+	// Só os limit melhores pares ficam em memória: o resto é descartado a cada lote, depois de ordenado
+	var duplicates []models.ClientDuplicate
+	byName := minScore <= models.DuplicateNameMaxScore
+	err := c.repo.DuplicateCandidates(byName, duplicateCandidateBatch, func(pairs [][2]models.Client) error {
+		duplicates = append(duplicates, models.ScoreDuplicateCandidates(pairs, minScore)...)
+		models.SortClientDuplicates(duplicates)
+		if len(duplicates) > limit {
+			duplicates = duplicates[:limit]
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("[ERRO] Falha ao buscar clientes duplicados: %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+		return
+	}
+	if duplicates == nil {
+		duplicates = []models.ClientDuplicate{}
+	}
+	ctx.JSON(http.StatusOK, duplicates)
+}
+
+// MergeClients godoc
+// @Summary      Mescla dois clientes
+// @Description  Combina remove_id em keep_id. Em fields escolha, por campo, de qual cliente vem o valor ("keep" ou "remove");
+// @Description  sem escolha vale o de keep_id, ou o de remove_id quando keep_id não tem o campo preenchido. O endereço é escolhido inteiro pelo campo address.
+// @Description  O cliente removido é excluído e seu ID passa a ser um alias: GET /clients/{remove_id} retorna o cliente mesclado.
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Param        body body mergeClientsRequest true "Clientes e escolhas por campo"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Documento ou e-mail já usado por outro cliente: {error, field, client_id}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/merge [post]
+// @Security     ApiKeyAuth
+func (c *ClientDuplicateController) Merge(ctx *gin.Context) {
+	var req mergeClientsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	for _, id := range []string{req.KeepID, req.RemoveID} {
+		if _, err := uuid.Parse(id); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "keep_id e remove_id devem ser UUIDs válidos"})
+			return
+		}
+	}
+	keep, ok := c.findClient(ctx, req.KeepID)
+	if !ok {
+		return
+	}
+	remove, ok := c.findClient(ctx, req.RemoveID)
+	if !ok {
+		return
+	}
+	// Compara depois de carregar: um dos IDs pode ser alias do outro
+	if keep.ID == remove.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "keep_id e remove_id devem ser clientes diferentes"})
+		return
+	}
+
+	merged, errs := models.MergeClients(&keep, &remove, req.Fields)
+	if errs != nil {
+		respondValidationErrors(ctx, errs)
+		return
+	}
+	if errs := merged.Validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
+		return
+	}
+	if err := c.repo.WithAudit(requestAudit(ctx)).Merge(&merged, remove.ID); err != nil {
+		if !respondClientConflict(ctx, err) {
+			fmt.Printf("[ERRO] Falha ao mesclar cliente %s em %s: %v\n", remove.ID, keep.ID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao mesclar clientes"})
+		}
+		return
+	}
+	fmt.Printf("[INFO] Cliente %s mesclado em %s\n", remove.ID, merged.ID)
+	ctx.JSON(http.StatusOK, gin.H{"client": merged, "alias_id": remove.ID})
+}
+
+// findClient carrega o cliente pelo ID (ou alias). Em caso de erro já escreve a resposta e retorna ok=false.
+func (c *ClientDuplicateController) findClient(ctx *gin.Context, id string) (models.Client, bool) {
+	client, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cliente %s não encontrado", id)})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cliente"})
+		}
+		return models.Client{}, false
+	}
+	return client, true
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/utils"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// enrichTimeout limita cada consulta ao cadastro de empresas, incluindo a espera pelo limite de consultas
+const enrichTimeout = 10 * time.Second
+
+// errNotCompany indica um cliente sem CNPJ válido, que não pode ser consultado no cadastro de empresas
+var errNotCompany = errors.New("o cliente não tem um CNPJ válido")
+
+type ClientEnrichController struct {
+	repo     *repositories.ClientRepository
+	registry utils.CompanyRegistry
+}
+
+func NewClientEnrichController(repo *repositories.ClientRepository, registry utils.CompanyRegistry) *ClientEnrichController {
+	return &ClientEnrichController{repo: repo, registry: registry}
+}
+
+// EnrichClient godoc
+// @Summary      Completa o cliente com os dados do CNPJ
+// @Description  Consulta o CNPJ do cliente no cadastro de empresas (BrasilAPI ou outra API configurada em COMPANY_REGISTRY_URL) e preenche
+// @Description  razão social (name), email, telefone e endereço. Sem overwrite só campos vazios são preenchidos, e o endereço só se o cliente não tiver um.
+// @Description  Dados do cadastro que não passam na validação são ignorados. As consultas ficam em cache (COMPANY_REGISTRY_CACHE_TTL) e
+// @Description  respeitam o limite de COMPANY_REGISTRY_RATE consultas por minuto.
+// @Tags         clients
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Param        overwrite query bool false "Substitui também os campos já preenchidos"
+// @Success      200 {object} map[string]interface{} "{client, enriched_fields, company}"
+// @Failure      400 {object} map[string]string "Cliente sem CNPJ"
+// @Failure      404 {object} map[string]string "Cliente ou CNPJ não encontrado"
+// @Failure      409 {object} map[string]string "E-mail do cadastro já usado por outro cliente: {error, field, client_id}"
+// @Failure      429 {object} map[string]string
+// @Failure      502 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/enrich [post]
+// @Security     ApiKeyAuth
+func (c *ClientEnrichController) Enrich(ctx *gin.Context) {
+	id, ok := clientIDParam(ctx)
+	if !ok {
+		return
+	}
+	client, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cliente"})
+		}
+		return
+	}
+	info, changed, err := enrichClient(ctx.Request.Context(), c.registry, &client, ctx.Query("overwrite") == "true")
+	switch {
+	case errors.Is(err, errNotCompany):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Só clientes com CNPJ podem ser completados pelo cadastro de empresas"})
+		return
+	case errors.Is(err, utils.ErrCompanyNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, utils.ErrRateLimited):
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "Limite de consultas ao cadastro de empresas atingido, tente novamente em instantes"})
+		return
+	case err != nil:
+		fmt.Printf("[ERRO] Falha ao consultar o CNPJ do cliente %s: %v\n", id, err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Falha ao consultar o cadastro de empresas"})
+		return
+	}
+
+	if len(changed) > 0 {
+		client.Validate() // normaliza endereço e telefone; os campos inválidos vindos do cadastro já foram descartados
+		if err := c.repo.WithAudit(requestAudit(ctx)).Update(&client); err != nil {
+			if !respondClientConflict(ctx, err) {
+				fmt.Printf("[ERRO] Falha ao gravar cliente %s completado pelo CNPJ: %v\n", id, err)
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar cliente"})
+			}
+			return
+		}
+		fmt.Printf("[INFO] Cliente %s completado pelo CNPJ: %s\n", id, strings.Join(changed, ", "))
+	}
+	clients := []models.Client{client}
+	if err := c.repo.LoadTags(clients); err != nil {
+		fmt.Printf("[ERRO] Falha ao buscar tags do cliente %s: %v\n", id, err)
+	}
+	if changed == nil {
+		changed = []string{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"client": clients[0], "enriched_fields": changed, "company": info})
+}
+
+// enrichClient consulta o CNPJ do cliente no cadastro de empresas e preenche os campos (ver
+// models.Client.ApplyCompanyInfo). Valores do cadastro que não passam na validação do cliente são descartados.
+// Retorna os dados da empresa e os campos alterados; o cliente não é gravado.
+func enrichClient(ctx context.Context, registry utils.CompanyRegistry, client *models.Client, overwrite bool) (*utils.CompanyInfo, []string, error) {
+	raw := client.Document
+	if raw == "" {
+		raw = client.CNPJ
+	}
+	doc, err := utils.ParseDocument(raw)
+	if err != nil || doc.Type != utils.DocumentTypeCNPJ {
+		return nil, nil, errNotCompany
+	}
+	ctx, cancel := context.WithTimeout(ctx, enrichTimeout)
+	defer cancel()
+	info, err := registry.LookupCNPJ(ctx, doc.Digits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	original := *client
+	changed := client.ApplyCompanyInfo(info, overwrite)
+	check := *client
+	for _, e := range check.Validate() {
+		if !slices.Contains(changed, e.Field) {
+			continue // erro que o cliente já tinha
+		}
+		for _, f := range models.ClientFields {
+			if f.Name == e.Field {
+				f.Set(client, f.Get(&original))
+			}
+		}
+		changed = slices.DeleteFunc(changed, func(name string) bool { return name == e.Field })
+	}
+	return info, changed, nil
+}
@@ -1,17 +1,22 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"minha-api/models"
 	"minha-api/repositories"
 	"minha-api/utils"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/xuri/excelize/v2"
 )
 
 type ClientExportController struct {
@@ -24,54 +29,139 @@ func NewClientExportController(repo *repositories.ClientRepository, uploader uti
 	return &ClientExportController{repo: repo, s3uploader: uploader, s3presigner: presigner}
 }
 
+// clientExportColumn define uma coluna disponível na exportação. Key é o nome usado em ?columns= e no JSON Lines.
+// Colunas de contato têm Contact em vez de Value: com elas o arquivo traz uma linha por contato.
+type clientExportColumn struct {
+	Key     string
+	Header  string
+	Value   func(c *models.Client) string
+	Contact func(ct *models.ClientContact) string
+}
+
+// clientExportColumns lista as colunas disponíveis, na ordem padrão do arquivo
+var clientExportColumns = []clientExportColumn{
+	{"id", "ID", func(c *models.Client) string { return c.ID }, nil},
+	{"name", "Nome", func(c *models.Client) string { return c.Name }, nil},
+	{"email", "Email", func(c *models.Client) string { return c.Email }, nil},
+	{"phone", "Telefone", func(c *models.Client) string { return c.Phone }, nil},
+	{"address", "Endereço", func(c *models.Client) string { return c.Address }, nil},
+	{"cnpj", "CNPJ", func(c *models.Client) string { return c.CNPJ }, nil},
+	{"document", "CPF/CNPJ", func(c *models.Client) string { return utils.FormatDocument(c.Document) }, nil},
+	{"person_type", "Tipo Pessoa", func(c *models.Client) string { return c.PersonType }, nil},
+	{"phone_e164", "Telefone (E.164)", func(c *models.Client) string { return c.PhoneE164 }, nil},
+	{"address_parts.street", "Logradouro", func(c *models.Client) string { return c.AddressParts.Street }, nil},
+	{"address_parts.number", "Número", func(c *models.Client) string { return c.AddressParts.Number }, nil},
+	{"address_parts.complement", "Complemento", func(c *models.Client) string { return c.AddressParts.Complement }, nil},
+	{"address_parts.neighborhood", "Bairro", func(c *models.Client) string { return c.AddressParts.Neighborhood }, nil},
+	{"address_parts.city", "Cidade", func(c *models.Client) string { return c.AddressParts.City }, nil},
+	{"address_parts.uf", "UF", func(c *models.Client) string { return c.AddressParts.UF }, nil},
+	{"address_parts.cep", "CEP", func(c *models.Client) string { return utils.FormatCEP(c.AddressParts.CEP) }, nil},
+}
+
+// clientContactExportColumns são as colunas de contato. Ficam fora do padrão: são exportadas quando pedidas
+// em ?columns= ou com ?contacts=rows. Os cabeçalhos são reconhecidos pela importação.
+var clientContactExportColumns = []clientExportColumn{
+	{"contact.type", "Tipo Contato", nil, func(ct *models.ClientContact) string { return ct.Type }},
+	{"contact.name", "Nome Contato", nil, func(ct *models.ClientContact) string { return ct.Name }},
+	{"contact.email", "Email Contato", nil, func(ct *models.ClientContact) string { return ct.Email }},
+	{"contact.phone", "Telefone Contato", nil, func(ct *models.ClientContact) string { return ct.Phone }},
+	{"contact.primary", "Contato Principal", nil, func(ct *models.ClientContact) string {
+		if ct.Primary {
+			return "sim"
+		}
+		return ""
+	}},
+}
+
+// csvDelimiters são os valores aceitos em ?delimiter=
+var csvDelimiters = map[string]rune{";": ';', ",": ',', "|": '|', "tab": '\t', "\t": '\t'}
+
+// clientExportRequest reúne as opções de exportação lidas da requisição
+type clientExportRequest struct {
+	Query   url.Values // parâmetros originais, com o formato já resolvido
+	Format  utils.ExportFormat
+	Columns []clientExportColumn
+	// ContactRows indica que há colunas de contato: cada contato vira uma linha, repetindo os dados do cliente
+	ContactRows bool
+	Options     utils.ExportOptions
+	Filter      repositories.ClientFilter
+}
+
+// Formas de entrega do arquivo em GET /clients/export
+const (
+	exportDeliveryS3     = "s3"     // salva no S3 e retorna um link temporário
+	exportDeliveryStream = "stream" // envia o arquivo direto na resposta, para instalações sem bucket
+)
+
 // ExportClients godoc
-// @Summary      Exporta todos os clientes em XLS e salva no S3
-// @Description  Gera um arquivo XLS com todos os clientes do banco e retorna um link temporário para download do arquivo salvo no S3. O arquivo contém as colunas: ID, Nome, Email, Telefone, Endereço, CNPJ.
+// @Summary      Exporta clientes em XLSX, CSV ou JSON Lines
+// @Description  Gera o arquivo com os clientes que atendem aos mesmos filtros de GET /clients. Com delivery=s3 o arquivo é salvo no S3 e a resposta traz um link temporário para download;
+// @Description  com delivery=stream o arquivo é enviado direto na resposta, como anexo, à medida que é gerado. Sem delivery vale EXPORT_DELIVERY (padrão s3).
+// @Description  O formato vem de ?format= ou, na falta dele, do cabeçalho Accept (padrão XLSX). Em columns escolha as colunas e sua ordem, pelas chaves:
+// @Description  id, name, email, phone, address, cnpj, document, person_type, phone_e164, address_parts.street, address_parts.number, address_parts.complement,
+// @Description  address_parts.neighborhood, address_parts.city, address_parts.uf, address_parts.cep. Sem columns, todas são exportadas.
+// @Description  As colunas de contato (contact.type, contact.name, contact.email, contact.phone, contact.primary) geram uma linha por contato, com os dados
+// @Description  do cliente repetidos e as linhas do mesmo cliente juntas; clientes sem contato saem em uma linha com as colunas de contato vazias.
+// @Description  contacts=rows acrescenta todas as colunas de contato às escolhidas.
+// @Description  O CSV usa por padrão separador ";" e BOM UTF-8, como o Excel em português espera.
 // @Tags         clients
-// @Produce      json
+// @Produce      json,octet-stream
+// @Param        format query string false "xlsx, csv ou jsonl"
+// @Param        delivery query string false "s3 ou stream"
+// @Param        columns query string false "Colunas separadas por vírgula, na ordem desejada" example(name,email,address_parts.city)
+// @Param        delimiter query string false "Separador do CSV: ; , | ou tab (padrão ;)"
+// @Param        bom query bool false "Grava o BOM UTF-8 no CSV (padrão true)"
+// @Param        contacts query string false "rows: uma linha por contato do cliente" Enums(rows)
+// @Param        phone query string false "Telefone em qualquer formato"
+// @Param        city query string false "Cidade"
+// @Param        uf query string false "UF (sigla do estado)"
+// @Param        cep query string false "CEP"
+// @Param        importJobId query string false "Somente clientes criados ou alterados por último nessa importação"
+// @Param        tag query string false "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)"
+// @Param        withoutTag query string false "Somente clientes sem nenhuma dessas tags"
 // @Success      200 {object} map[string]string "Exemplo de resposta: {\"download_url\":\"https://bucket.s3.amazonaws.com/clientes_export_20250703_153000.xlsx\"}"
-// @Failure      500 {object} map[string]string "Erro ao buscar clientes, gerar XLS ou enviar para S3"
+// @Failure      400 {object} map[string]interface{} "Formato não suportado ou lista de erros de validação {field, code, message}"
+// @Failure      500 {object} map[string]string "Erro ao buscar clientes, gerar o arquivo ou enviar para S3"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
 // @Router       /clients/export [get]
+// @Security     ApiKeyAuth
 func (c *ClientExportController) ExportClients(ctx *gin.Context) {
-	clients, err := c.repo.GetAll()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+	delivery := strings.ToLower(strings.TrimSpace(ctx.Query("delivery")))
+	if delivery == "" {
+		delivery = strings.ToLower(strings.TrimSpace(os.Getenv("EXPORT_DELIVERY")))
+	}
+	if delivery == "" {
+		delivery = exportDeliveryS3
+	}
+	if delivery != exportDeliveryS3 && delivery != exportDeliveryStream {
+		respondValidationErrors(ctx, utils.ValidationErrors{{Field: "delivery", Code: utils.CodeInvalidValue, Message: "Use s3 ou stream"}})
 		return
 	}
-
-	xl := excelize.NewFile()
-	sheet := "Clientes"
-	idx, err := xl.NewSheet(sheet)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar nova aba no XLS"})
+	req, ok := parseClientExportRequest(ctx)
+	if !ok {
 		return
 	}
-	xl.SetActiveSheet(idx)
-	headers := []string{"ID", "Nome", "Email", "Telefone", "Endereço", "CNPJ"}
-	for i, h := range headers {
-		col, _ := excelize.CoordinatesToCellName(i+1, 1)
-		xl.SetCellValue(sheet, col, h)
+	if delivery == exportDeliveryStream {
+		c.streamClients(ctx, req)
+		return
 	}
-	for idx, client := range clients {
-		row := idx + 2
-		xl.SetCellValue(sheet, fmt.Sprintf("A%d", row), client.ID)
-		xl.SetCellValue(sheet, fmt.Sprintf("B%d", row), client.Name)
-		xl.SetCellValue(sheet, fmt.Sprintf("C%d", row), client.Email)
-		xl.SetCellValue(sheet, fmt.Sprintf("D%d", row), client.Phone)
-		xl.SetCellValue(sheet, fmt.Sprintf("E%d", row), client.Address)
-		xl.SetCellValue(sheet, fmt.Sprintf("F%d", row), client.CNPJ)
+	clients, err := c.repo.Find(req.Filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+		return
 	}
 
-	fileName := "clientes_export_" + time.Now().Format("20060102_150405") + ".xlsx"
-	buf, err := xl.WriteToBuffer()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar XLS"})
+	var buf bytes.Buffer
+	if err := writeClientExport(&buf, req, c.repo, clients); err != nil {
+		fmt.Printf("[ERRO] Falha ao gerar exportação %s: %v\n", req.Format.Name, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar arquivo de exportação"})
 		return
 	}
 
-	_, err = c.s3uploader.UploadToS3(context.Background(), fileName, buf)
+	fileName := "clientes_export_" + time.Now().Format("20060102_150405") + req.Format.Extension
+	_, err = c.s3uploader.UploadToS3(context.Background(), fileName, &buf)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao enviar XLS para S3", "details": err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao enviar arquivo para S3", "details": err.Error()})
 		return
 	}
 
@@ -85,3 +175,231 @@ func (c *ClientExportController) ExportClients(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"download_url": presignedURL})
 }
+
+// streamClients grava o arquivo direto na resposta, lendo os clientes em lotes: a memória usada não cresce
+// com a quantidade de clientes (o XLSX usa o StreamWriter do excelize, que descarrega as linhas em disco).
+// Os cabeçalhos só são enviados depois do primeiro lote, para uma falha na consulta ainda virar um erro 500.
+func (c *ClientExportController) streamClients(ctx *gin.Context, req clientExportRequest) {
+	var cw *clientExportWriter
+	start := func() error {
+		fileName := "clientes_export_" + time.Now().Format("20060102_150405") + req.Format.Extension
+		ctx.Header("Content-Type", req.Format.ContentType)
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		ctx.Status(http.StatusOK)
+		var err error
+		cw, err = newClientExportWriter(ctx.Writer, req, c.repo)
+		return err
+	}
+	err := c.repo.FindInBatches(req.Filter, exportBatchSize, func(clients []models.Client) error {
+		if cw == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return cw.Write(clients)
+	})
+	if err == nil && cw == nil {
+		err = start() // nenhum cliente: arquivo só com o cabeçalho das colunas
+	}
+	if cw != nil {
+		if closeErr := cw.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err == nil {
+		return
+	}
+	fmt.Printf("[ERRO] Falha ao enviar exportação %s: %v\n", req.Format.Name, err)
+	if !ctx.Writer.Written() {
+		ctx.Header("Content-Type", "")
+		ctx.Header("Content-Disposition", "")
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar arquivo de exportação"})
+		return
+	}
+	abortConnection(ctx)
+}
+
+// abortConnection fecha a conexão no meio de uma resposta já iniciada. Com o cabeçalho enviado não há como
+// responder com erro; fechando a conexão o cliente percebe que o arquivo veio incompleto, em vez de recebê-lo
+// truncado como se estivesse completo.
+func abortConnection(ctx *gin.Context) {
+	ctx.Abort()
+	w, ok := ctx.Writer.(interface{ Unwrap() http.ResponseWriter })
+	if !ok {
+		return
+	}
+	if hj, ok := w.Unwrap().(http.Hijacker); ok {
+		if conn, _, err := hj.Hijack(); err == nil {
+			conn.Close()
+		}
+	}
+}
+
+// parseClientExportRequest lê formato, colunas, opções do CSV e filtros. Sem ?format= o formato vem do Accept.
+// Em caso de erro já escreve a resposta e retorna ok=false.
+func parseClientExportRequest(ctx *gin.Context) (clientExportRequest, bool) {
+	q := ctx.Request.URL.Query()
+	if q.Get("format") == "" {
+		format := utils.ExportFormats[0]
+		if accept := ctx.GetHeader("Accept"); accept != "" {
+			f, ok := utils.ExportFormatFromAccept(accept)
+			if !ok {
+				ctx.JSON(http.StatusNotAcceptable, gin.H{"error": "Formato não suportado no Accept. Use xlsx, csv ou jsonl."})
+				return clientExportRequest{}, false
+			}
+			format = f
+		}
+		q.Set("format", format.Name)
+	}
+	req, errs := parseClientExportValues(q)
+	if errs != nil {
+		respondValidationErrors(ctx, errs)
+		return req, false
+	}
+	return req, true
+}
+
+// parseClientExportValues interpreta os parâmetros de exportação; q deve trazer o formato
+func parseClientExportValues(q url.Values) (clientExportRequest, utils.ValidationErrors) {
+	req := clientExportRequest{Query: q, Filter: clientFilterFromValues(q), Options: utils.ExportOptions{BOM: true, SheetName: "Clientes"}}
+	var v utils.Validator
+
+	f, ok := utils.LookupExportFormat(q.Get("format"))
+	if !ok {
+		v.Add("format", utils.CodeInvalidValue, "Use xlsx, csv ou jsonl")
+	}
+	req.Format = f
+
+	if s := strings.TrimSpace(q.Get("columns")); s != "" {
+		seen := map[string]bool{}
+		for _, key := range strings.Split(s, ",") {
+			key = strings.ToLower(strings.TrimSpace(key))
+			col, ok := findClientExportColumn(key)
+			switch {
+			case !ok:
+				v.Add("columns", utils.CodeInvalidValue, fmt.Sprintf("Coluna desconhecida: %s", key))
+			case seen[key]:
+				v.Add("columns", utils.CodeInvalidValue, fmt.Sprintf("Coluna repetida: %s", key))
+			default:
+				seen[key] = true
+				req.Columns = append(req.Columns, col)
+			}
+		}
+	} else {
+		req.Columns = clientExportColumns
+	}
+	switch strings.ToLower(q.Get("contacts")) {
+	case "":
+	case "rows":
+		for _, col := range clientContactExportColumns {
+			if !slices.ContainsFunc(req.Columns, func(c clientExportColumn) bool { return c.Key == col.Key }) {
+				req.Columns = append(req.Columns, col)
+			}
+		}
+	default:
+		v.Add("contacts", utils.CodeInvalidValue, "Use rows")
+	}
+	req.ContactRows = slices.ContainsFunc(req.Columns, func(c clientExportColumn) bool { return c.Contact != nil })
+
+	if s := q.Get("delimiter"); s != "" {
+		d, ok := csvDelimiters[strings.ToLower(s)]
+		if !ok {
+			v.Add("delimiter", utils.CodeInvalidValue, "Use ; , | ou tab")
+		}
+		req.Options.Delimiter = d
+	}
+	if s := q.Get("bom"); s != "" {
+		bom, err := strconv.ParseBool(s)
+		if err != nil {
+			v.Add("bom", utils.CodeInvalidValue, "Use true ou false")
+		}
+		req.Options.BOM = bom
+	}
+	return req, v.Errors()
+}
+
+func findClientExportColumn(key string) (clientExportColumn, bool) {
+	for _, col := range slices.Concat(clientExportColumns, clientContactExportColumns) {
+		if col.Key == key {
+			return col, true
+		}
+	}
+	return clientExportColumn{}, false
+}
+
+// writeClientExport grava os clientes em w no formato e com as colunas pedidas
+func writeClientExport(w io.Writer, req clientExportRequest, repo *repositories.ClientRepository, clients []models.Client) error {
+	cw, err := newClientExportWriter(w, req, repo)
+	if err != nil {
+		return err
+	}
+	if err := cw.Write(clients); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+// clientExportWriter grava clientes aos poucos no formato e com as colunas pedidas
+type clientExportWriter struct {
+	exp     utils.Exporter
+	columns []clientExportColumn
+	values  []string
+	repo    *repositories.ClientRepository // carrega os contatos de cada lote; nil quando não há colunas de contato
+}
+
+// newClientExportWriter prepara o arquivo. repo só é usado quando há colunas de contato.
+func newClientExportWriter(w io.Writer, req clientExportRequest, repo *repositories.ClientRepository) (*clientExportWriter, error) {
+	columns := make([]utils.ExportColumn, len(req.Columns))
+	for i, col := range req.Columns {
+		columns[i] = utils.ExportColumn{Key: col.Key, Header: col.Header}
+	}
+	exp, err := utils.NewExporter(req.Format.Name, w, columns, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	cw := &clientExportWriter{exp: exp, columns: req.Columns, values: make([]string, len(req.Columns))}
+	if req.ContactRows {
+		cw.repo = repo
+	}
+	return cw, nil
+}
+
+// Write grava um lote de clientes. Com colunas de contato, busca os contatos do lote de uma vez e grava
+// uma linha por contato.
+func (cw *clientExportWriter) Write(clients []models.Client) error {
+	var contacts map[string][]models.ClientContact
+	if cw.repo != nil {
+		ids := make([]string, len(clients))
+		for i := range clients {
+			ids[i] = clients[i].ID
+		}
+		var err error
+		if contacts, err = cw.repo.ContactsByClientIDs(ids); err != nil {
+			return err
+		}
+	}
+	for i := range clients {
+		rows := contacts[clients[i].ID]
+		if len(rows) == 0 {
+			rows = []models.ClientContact{{}} // cliente sem contato: uma linha com as colunas de contato vazias
+		}
+		for k := range rows {
+			for j, col := range cw.columns {
+				if col.Contact != nil {
+					cw.values[j] = col.Contact(&rows[k])
+				} else {
+					cw.values[j] = col.Value(&clients[i])
+				}
+			}
+			if err := cw.exp.WriteRow(cw.values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cw *clientExportWriter) Close() error {
+	return cw.exp.Close()
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"minha-api/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ClientHistoryController struct {
+	repo *repositories.ClientRepository
+}
+
+func NewClientHistoryController(repo *repositories.ClientRepository) *ClientHistoryController {
+	return &ClientHistoryController{repo: repo}
+}
+
+// ClientHistory godoc
+// @Summary      Histórico de alterações do cliente
+// @Description  Lista as versões do cliente, da mais recente para a mais antiga: ação (created, updated, deleted, merged, restored),
+// @Description  campos alterados com valor antigo e novo, autor (usuário da API key), ID da requisição (X-Request-ID) e data.
+// @Description  Clientes excluídos continuam com histórico.
+// @Tags         clients
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Success      200 {array} models.ClientRevision
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/history [get]
+// @Security     ApiKeyAuth
+func (c *ClientHistoryController) History(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	revisions, err := c.repo.History(id)
+	if err != nil {
+		fmt.Printf("[ERRO] Falha ao buscar histórico do cliente %s: %v\n", id, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico do cliente"})
+		return
+	}
+	if len(revisions) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+		return
+	}
+	ctx.JSON(http.StatusOK, revisions)
+}
+
+// RestoreClientRevision godoc
+// @Summary      Restaura o cliente a uma versão do histórico
+// @Description  Volta os dados do cliente aos da versão informada e registra uma nova versão "restored"; o histórico não é apagado.
+// @Description  Um cliente excluído volta a existir. Versões de exclusão não podem ser restauradas.
+// @Tags         clients
+// @Produce      json
+// @Param        id path string true "ID do cliente"
+// @Param        version path int true "Versão a restaurar"
+// @Success      200 {object} models.Client
+// @Failure      400 {object} map[string]string "Versão de exclusão ou com documento inválido"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Documento ou e-mail da versão já usado por outro cliente: {error, field, client_id}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/{id}/history/{version}/restore [post]
+// @Security     ApiKeyAuth
+func (c *ClientHistoryController) Restore(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Versão inválida"})
+		return
+	}
+	client, err := c.repo.WithAudit(requestAudit(ctx)).Restore(id, version)
+	switch {
+	case errors.Is(err, repositories.ErrRevisionNotRestorable):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A versão %d é uma exclusão e não pode ser restaurada", version)})
+		return
+	case errors.Is(err, repositories.ErrRevisionInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A versão %d não pode ser restaurada: %v", version, err)})
+		return
+	case err != nil && strings.Contains(err.Error(), "record not found"):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Versão não encontrada"})
+		return
+	case respondClientConflict(ctx, err):
+		return
+	case err != nil:
+		fmt.Printf("[ERRO] Falha ao restaurar cliente %s na versão %d: %v\n", id, version, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao restaurar cliente"})
+		return
+	}
+	fmt.Printf("[INFO] Cliente %s restaurado à versão %d\n", id, version)
+	ctx.JSON(http.StatusOK, client)
+}
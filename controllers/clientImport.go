@@ -0,0 +1,610 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/utils"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// importRow é uma linha de dados da planilha já convertida em cliente
+type importRow struct {
+	Seq    int    // posição da linha no arquivo, contando todas as abas
+	Sheet  string // aba de origem
+	Line   int    // número da linha na aba, como aparece no Excel
+	Client models.Client
+	// Contact é o contato trazido pela linha, quando a planilha tem as colunas de contato
+	Contact *models.ClientContact
+	// contacts são as linhas seguintes do mesmo cliente que só acrescentam contatos, gravados junto com ele
+	contacts []importContactRow
+	// resultado do enriquecimento pelo CNPJ (ver enrichImportRow)
+	enriched    []string
+	enrichError string
+}
+
+// importContactRow é uma linha repetida de um cliente já visto no arquivo que traz um contato
+// (ex.: uma linha por contato, agrupadas pelo CNPJ)
+type importContactRow struct {
+	res     importRowResult
+	contact models.ClientContact
+}
+
+// importSeen é a primeira linha do arquivo com uma chave de correspondência
+type importSeen struct {
+	line     int
+	batch    int    // posição da linha no lote ainda não gravado; -1 depois que o lote foi processado
+	status   string // resultado da linha, conhecido depois que o lote foi processado
+	clientID string
+}
+
+// importSheet descreve uma aba lida do arquivo. Error preenchido indica que a aba não foi importada.
+type importSheet struct {
+	Name  string `json:"sheet"`
+	Error string `json:"error,omitempty"`
+}
+
+// importData é o conteúdo lido de um arquivo de importação
+type importData struct {
+	FileName string
+	Params   map[string]string // parâmetros de leitura (templateId, sheet...), registrados no job
+	Sheets   []importSheet
+	Rows     []importRow
+	Partial  bool // passou do limite de linhas da pré-visualização: Rows não foi guardado
+}
+
+// importSheetSummary é o resultado da importação de uma aba
+type importSheetSummary struct {
+	importSheet
+	Totals map[string]int `json:"totals"`
+}
+
+// Situações possíveis de uma linha na importação
+const (
+	importStatusWouldInsert = "would_insert" // apenas em dry-run
+	importStatusWouldUpdate = "would_update" // apenas em dry-run
+	importStatusInserted    = "inserted"
+	importStatusUpdated     = "updated"
+	importStatusUnchanged   = "unchanged"
+	importStatusDuplicate   = "duplicate" // ignorada: já existe (modo insert-only) ou repetida no arquivo
+	importStatusInvalid     = "invalid"
+	importStatusError       = "error"
+	importStatusRolledBack  = "rolled_back" // gravada, mas desfeita porque a importação atômica foi cancelada
+	// linha repetida de um cliente que acrescenta um contato a ele
+	importStatusContactAdded    = "contact_added"
+	importStatusWouldAddContact = "would_add_contact" // apenas em dry-run
+)
+
+// Modos de importação
+const (
+	importModeInsertOnly = "insert-only" // só insere; clientes já existentes são ignorados
+	importModeUpsert     = "upsert"      // atualiza os campos preenchidos que mudaram
+	importModeReplace    = "replace"     // substitui todos os campos do cliente existente, inclusive limpando os vazios
+)
+
+// Chaves usadas para encontrar o cliente já cadastrado que corresponde a uma linha
+const (
+	importMatchAuto      = "auto" // nome+documento quando há documento, senão nome+email
+	importMatchDocument  = "document"
+	importMatchEmail     = "email"
+	importMatchNameEmail = "name+email"
+)
+
+// Modos de transação da importação
+const (
+	importAtomicAll       = "true"      // tudo ou nada: qualquer linha inválida ou com erro desfaz a importação inteira
+	importAtomicSavepoint = "savepoint" // linhas com problema são puladas e as demais são confirmadas juntas no fim
+)
+
+// importOptions reúne os parâmetros que controlam o processamento das linhas
+type importOptions struct {
+	DryRun   bool   `json:"dry_run"`
+	Mode     string `json:"mode"`
+	MatchKey string `json:"match_key"`
+	Atomic   string `json:"atomic,omitempty"` // vazio: cada linha é gravada assim que processada
+	Enrich   bool   `json:"enrich,omitempty"` // completa as linhas com CNPJ pelo cadastro de empresas
+	JobID    string `json:"-"`                // job ao qual as gravações são associadas
+}
+
+// importRowResult descreve o que aconteceu (ou aconteceria, em dry-run) com uma linha
+type importRowResult struct {
+	Sheet    string                 `json:"sheet"`
+	Line     int                    `json:"line"`
+	Status   string                 `json:"status"`
+	ClientID string                 `json:"client_id,omitempty"` // cliente criado, atualizado ou existente do qual a linha é duplicada
+	Field    string                 `json:"field,omitempty"`     // primeiro campo inválido
+	Message  string                 `json:"message,omitempty"`
+	Errors   utils.ValidationErrors `json:"errors,omitempty"`         // todos os erros de validação da linha
+	Changed  []string               `json:"changed_fields,omitempty"` // campos alterados em upsert/replace
+	// Enriched são os campos completados pelo cadastro de empresas (enrich=true); EnrichError, o motivo de não completar
+	Enriched    []string `json:"enriched_fields,omitempty"`
+	EnrichError string   `json:"enrich_error,omitempty"`
+	seq         int
+}
+
+// validate confere modo e chave de correspondência, aplicando os padrões
+func (o *importOptions) validate() error {
+	switch o.Mode {
+	case "":
+		o.Mode = importModeInsertOnly
+	case importModeInsertOnly, importModeUpsert, importModeReplace:
+	default:
+		return fmt.Errorf("Modo de importação inválido. Use insert-only, upsert ou replace")
+	}
+	switch o.MatchKey {
+	case "":
+		o.MatchKey = importMatchAuto
+	case importMatchAuto, importMatchDocument, importMatchEmail, importMatchNameEmail:
+	default:
+		return fmt.Errorf("Chave de correspondência inválida. Use document, email ou name+email")
+	}
+	switch o.Atomic {
+	case "", "false":
+		o.Atomic = ""
+	case importAtomicAll, importAtomicSavepoint:
+	default:
+		return fmt.Errorf("Valor de atomic inválido. Use true ou savepoint")
+	}
+	return nil
+}
+
+// importBatchSize é a quantidade de linhas consultadas e inseridas por vez
+const importBatchSize = 500
+
+// processImport valida cada linha recebida, descarta as repetidas dentro do próprio arquivo e, em lotes,
+// procura os clientes já cadastrados com poucas consultas e insere os novos em INSERTs de vários registros.
+// Atualizações (upsert/replace) continuam uma a uma. Usa o repositório informado, que pode estar em uma
+// transação, e associa cada cliente gravado ao job opts.JobID. Em dry-run nada é gravado.
+// O resultado de cada linha é passado para emit assim que conhecido, fora da ordem do arquivo.
+// Uma linha repetida que traz um contato não é descartada: o contato é acrescentado ao cliente da primeira linha.
+func (c *ClientController) processImport(repo *repositories.ClientRepository, rows <-chan importRow, opts importOptions, emit func(importRowResult)) {
+	seen := map[string]*importSeen{} // chave de correspondência -> primeira linha em que apareceu
+	batch := make([]importRow, 0, importBatchSize)
+	batchSeen := make([]*importSeen, 0, importBatchSize)
+	flush := func() {
+		results := c.processBatch(repo, batch, opts, emit)
+		for i, s := range batchSeen {
+			if s != nil {
+				s.batch, s.status, s.clientID = -1, results[i].Status, results[i].ClientID
+			}
+		}
+		batch, batchSeen = batch[:0], batchSeen[:0]
+	}
+	for row := range rows {
+		res := importRowResult{Sheet: row.Sheet, Line: row.Line, seq: row.Seq}
+		client := &row.Client
+		if opts.Enrich {
+			c.enrichImportRow(&row)
+			res.Enriched, res.EnrichError = row.enriched, row.enrichError
+		}
+
+		errs := client.Validate()
+		if row.Contact != nil {
+			for _, e := range row.Contact.Validate() {
+				e.Field = "contact_" + e.Field
+				errs = append(errs, e)
+			}
+		}
+		if errs != nil {
+			res.Status, res.Field, res.Message, res.Errors = importStatusInvalid, errs[0].Field, errs[0].Message, errs
+			emit(res)
+			continue
+		}
+		var first *importSeen
+		if key := matchKeyValue(opts.MatchKey, client); key != "" {
+			if s, ok := seen[key]; ok {
+				switch {
+				case row.Contact == nil:
+					res.Status, res.Message = importStatusDuplicate, fmt.Sprintf("Duplicado da linha %d", s.line)
+					emit(res)
+				case s.batch >= 0:
+					batch[s.batch].contacts = append(batch[s.batch].contacts, importContactRow{res: res, contact: *row.Contact})
+				default:
+					res.Status, res.Message = addImportContacts(repo, s.status, s.clientID, s.line, []models.ClientContact{*row.Contact}, opts)
+					res.ClientID = s.clientID
+					emit(res)
+				}
+				continue
+			}
+			first = &importSeen{line: row.Line, batch: len(batch)}
+			seen[key] = first
+		}
+		// No modo automático uma linha sem documento corresponde, pelo nome e email, a uma linha com documento
+		if opts.MatchKey == importMatchAuto && client.Document != "" {
+			if key := matchKeyValue(importMatchNameEmail, client); seen[key] == nil {
+				seen[key] = first
+			}
+		}
+
+		batch, batchSeen = append(batch, row), append(batchSeen, first)
+		if len(batch) == importBatchSize {
+			flush()
+		}
+	}
+	if len(batch) > 0 {
+		flush()
+	}
+}
+
+// enrichImportRow completa os campos vazios de uma linha com CNPJ pelo cadastro de empresas. Linhas sem CNPJ
+// válido ficam como estão (a validação aponta o problema); falhas na consulta não impedem a importação da linha.
+func (c *ClientController) enrichImportRow(row *importRow) {
+	if c.registry == nil {
+		return
+	}
+	_, changed, err := enrichClient(context.Background(), c.registry, &row.Client, false)
+	switch {
+	case errors.Is(err, errNotCompany):
+	case errors.Is(err, utils.ErrCompanyNotFound), errors.Is(err, utils.ErrRateLimited):
+		row.enrichError = err.Error()
+	case err != nil:
+		fmt.Printf("[ERRO] Falha ao consultar CNPJ (linha %d): %v\n", row.Line, err)
+		row.enrichError = "Falha ao consultar o cadastro de empresas"
+	default:
+		row.enriched = changed
+	}
+}
+
+// addImportContacts grava contatos no cliente de uma linha já processada, conforme o resultado dela, e
+// retorna a situação e a mensagem das linhas de contato. Contatos de clientes que não foram gravados
+// (inválidos, com erro ou ignorados no modo insert-only) são descartados como duplicados da linha.
+func addImportContacts(repo *repositories.ClientRepository, status, clientID string, line int, contacts []models.ClientContact, opts importOptions) (string, string) {
+	message := fmt.Sprintf("Contato do cliente da linha %d", line)
+	switch status {
+	case importStatusWouldInsert, importStatusWouldUpdate:
+		return importStatusWouldAddContact, message
+	case importStatusInserted, importStatusUpdated, importStatusUnchanged:
+		if opts.DryRun {
+			return importStatusWouldAddContact, message
+		}
+	default:
+		return importStatusDuplicate, fmt.Sprintf("Duplicado da linha %d", line)
+	}
+	for i := range contacts {
+		contacts[i].ID, contacts[i].ClientID = uuid.New().String(), clientID
+	}
+	if _, err := repo.CreateImportedContacts(opts.JobID, contacts); err != nil {
+		fmt.Printf("[ERRO] Falha ao gravar contatos do cliente %s (linha %d): %v\n", clientID, line, err)
+		return importStatusError, "Falha ao gravar contato"
+	}
+	return importStatusContactAdded, message
+}
+
+// saveBatchContacts grava, depois dos clientes do lote, os contatos trazidos pelas próprias linhas e pelas
+// linhas repetidas agrupadas nelas, e emite o resultado das linhas repetidas
+func saveBatchContacts(repo *repositories.ClientRepository, batch []importRow, results []importRowResult, opts importOptions, emit func(importRowResult)) {
+	for i := range batch {
+		row, res := &batch[i], &results[i]
+		if row.Contact == nil && len(row.contacts) == 0 {
+			continue
+		}
+		var contacts []models.ClientContact
+		if row.Contact != nil {
+			contacts = append(contacts, *row.Contact)
+		}
+		for _, cr := range row.contacts {
+			contacts = append(contacts, cr.contact)
+		}
+		status, message := addImportContacts(repo, res.Status, res.ClientID, res.Line, contacts, opts)
+		if status == importStatusError {
+			res.Status, res.Message = importStatusError, "Cliente gravado, mas houve falha ao gravar os contatos"
+		}
+		for _, cr := range row.contacts {
+			cr.res.Status, cr.res.Message, cr.res.ClientID = status, message, res.ClientID
+			emit(cr.res)
+		}
+	}
+}
+
+// processBatch procura de uma vez os clientes existentes das linhas (já validadas), aplica o modo a cada uma
+// e grava os contatos trazidos pelas linhas. Retorna o resultado de cada linha do lote, na mesma ordem.
+func (c *ClientController) processBatch(repo *repositories.ClientRepository, batch []importRow, opts importOptions, emit func(importRowResult)) []importRowResult {
+	results := make([]importRowResult, len(batch))
+	clients := make([]models.Client, len(batch))
+	for i, row := range batch {
+		results[i] = importRowResult{Sheet: row.Sheet, Line: row.Line, Enriched: row.enriched, EnrichError: row.enrichError, seq: row.Seq}
+		clients[i] = row.Client
+	}
+	defer func() {
+		// Os contatos são gravados depois dos clientes, quando o ID de cada um já é conhecido
+		saveBatchContacts(repo, batch, results, opts, emit)
+		for _, res := range results {
+			emit(res)
+		}
+	}()
+
+	existing, err := loadExisting(repo, opts.MatchKey, clients)
+	if err != nil {
+		fmt.Printf("[ERRO] Falha ao verificar duplicidade (linhas %d a %d): %v\n", results[0].Line, results[len(results)-1].Line, err)
+		for i := range results {
+			results[i].Status, results[i].Message = importStatusError, "Falha ao verificar duplicidade"
+		}
+		return results
+	}
+
+	var inserts []int
+	for i := range clients {
+		client, res := &clients[i], &results[i]
+		match := existing[matchKeyValue(opts.MatchKey, client)]
+		if match == nil {
+			if opts.DryRun {
+				res.Status = importStatusWouldInsert
+				continue
+			}
+			client.ID, client.ImportJobID = uuid.New().String(), opts.JobID
+			inserts = append(inserts, i)
+			continue
+		}
+
+		res.ClientID = match.ID
+		if opts.Mode == importModeInsertOnly {
+			fmt.Printf("[INFO] Cliente duplicado ignorado: nome='%s', documento='%s'\n", client.Name, client.Document)
+			res.Status, res.Message = importStatusDuplicate, fmt.Sprintf("Duplicado do cliente %s", match.ID)
+			continue
+		}
+
+		updated := *match
+		updated.ImportJobID = opts.JobID
+		res.Changed = models.MergeClientFields(&updated, client, opts.Mode == importModeReplace)
+		if len(res.Changed) == 0 {
+			res.Status = importStatusUnchanged
+			continue
+		}
+		if errs := updated.Validate(); errs != nil {
+			res.Status, res.Field, res.Message, res.Errors = importStatusInvalid, errs[0].Field, errs[0].Message, errs
+			continue
+		}
+		if opts.DryRun {
+			res.Status = importStatusWouldUpdate
+			continue
+		}
+		// Refaz a mesclagem sobre os dados atuais do cliente, que podem ter mudado desde loadExisting
+		updated, err := repo.SaveImported(opts.JobID, match.ID, res.Line, func(current *models.Client) error {
+			if res.Changed = models.MergeClientFields(current, client, opts.Mode == importModeReplace); len(res.Changed) == 0 {
+				return errImportUnchanged
+			}
+			if errs := current.Validate(); errs != nil {
+				return errs
+			}
+			return nil
+		})
+		var errs utils.ValidationErrors
+		switch {
+		case errors.Is(err, errImportUnchanged):
+			res.Status = importStatusUnchanged
+			continue
+		case errors.As(err, &errs):
+			res.Status, res.Field, res.Message, res.Errors = importStatusInvalid, errs[0].Field, errs[0].Message, errs
+			continue
+		case importConflict(res, err):
+			continue
+		case err != nil:
+			fmt.Printf("[ERRO] Falha ao atualizar cliente %s (linha %d): %v\n", match.ID, res.Line, err)
+			res.Status, res.Message = importStatusError, "Falha ao atualizar cliente"
+			continue
+		}
+		*match = updated // linhas seguintes do mesmo cliente partem dos dados já atualizados
+		res.Status = importStatusUpdated
+	}
+
+	insertImportRows(repo, inserts, clients, results, opts)
+	return results
+}
+
+// errImportUnchanged desfaz a atualização de um cliente que, relido com o lock, já tem os dados da linha
+var errImportUnchanged = errors.New("cliente sem alterações")
+
+// insertImportRows insere os novos clientes em um único INSERT de vários registros. Se o lote falhar,
+// ele é desfeito e as linhas são gravadas uma a uma para identificar quais têm problema.
+// Cada gravação roda em sua própria transação, ou em um savepoint nos modos atômicos, de modo que a falha
+// de uma linha não aborta a transação da importação.
+func insertImportRows(repo *repositories.ClientRepository, idx []int, clients []models.Client, results []importRowResult, opts importOptions) {
+	if len(idx) == 0 {
+		return
+	}
+	batch := make([]models.Client, len(idx))
+	lines := make([]int, len(idx))
+	for k, i := range idx {
+		batch[k], lines[k] = clients[i], results[i].Line
+	}
+	err := repo.CreateImported(opts.JobID, batch, lines, importBatchSize)
+	if err == nil {
+		for _, i := range idx {
+			results[i].Status, results[i].ClientID = importStatusInserted, clients[i].ID
+		}
+		return
+	}
+
+	fmt.Printf("[ERRO] Falha ao inserir lote de %d clientes, gravando linha a linha: %v\n", len(idx), err)
+	for _, i := range idx {
+		client, res := clients[i], &results[i]
+		if err := repo.CreateImported(opts.JobID, []models.Client{client}, []int{res.Line}, 1); err != nil {
+			if importConflict(res, err) {
+				continue
+			}
+			fmt.Printf("[ERRO] Falha ao inserir cliente (linha %d): %v\n", res.Line, err)
+			res.Status, res.Message = importStatusError, "Falha ao inserir cliente"
+			continue
+		}
+		res.Status, res.ClientID = importStatusInserted, client.ID
+	}
+}
+
+// importConflict marca como duplicada a linha cujo documento ou e-mail já é de outro cliente (índices únicos),
+// informando o campo e o cliente existente. Retorna false para outros erros.
+func importConflict(res *importRowResult, err error) bool {
+	var conflict *repositories.ClientConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	res.Status, res.Field, res.Message = importStatusDuplicate, conflict.Field, conflict.Error()
+	return true
+}
+
+// loadExisting busca, com uma consulta por tipo de chave, os clientes cadastrados que correspondem às linhas.
+// O resultado é indexado pelas mesmas chaves de matchKeyValue.
+func loadExisting(repo *repositories.ClientRepository, matchKey string, clients []models.Client) (map[string]*models.Client, error) {
+	var documents, emails []string
+	var nameDocuments, nameEmails [][]interface{}
+	for i := range clients {
+		client := &clients[i]
+		switch matchKey {
+		case importMatchDocument:
+			if client.Document != "" {
+				documents = append(documents, client.Document)
+			}
+		case importMatchEmail:
+			if client.Email != "" {
+				emails = append(emails, strings.ToLower(client.Email))
+			}
+		case importMatchNameEmail:
+			nameEmails = append(nameEmails, []interface{}{client.Name, client.Email})
+		default:
+			if client.Document != "" {
+				nameDocuments = append(nameDocuments, []interface{}{client.Name, client.Document})
+			} else {
+				nameEmails = append(nameEmails, []interface{}{client.Name, client.Email})
+			}
+		}
+	}
+
+	var found []models.Client
+	lookups := []struct {
+		pending bool
+		find    func() ([]models.Client, error)
+	}{
+		{len(documents) > 0, func() ([]models.Client, error) { return repo.FindByDocuments(documents) }},
+		{len(emails) > 0, func() ([]models.Client, error) { return repo.FindByEmails(emails) }},
+		{len(nameDocuments) > 0, func() ([]models.Client, error) { return repo.FindByNameAndDocumentPairs(nameDocuments) }},
+		{len(nameEmails) > 0, func() ([]models.Client, error) { return repo.FindByNameAndEmailPairs(nameEmails) }},
+	}
+	for _, l := range lookups {
+		if !l.pending {
+			continue
+		}
+		clients, err := l.find()
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, clients...)
+	}
+
+	existing := map[string]*models.Client{}
+	for i := range found {
+		for _, key := range existingKeys(matchKey, &found[i]) {
+			if _, ok := existing[key]; !ok {
+				existing[key] = &found[i]
+			}
+		}
+	}
+	return existing, nil
+}
+
+// existingKeys retorna as chaves pelas quais um cliente cadastrado pode ser encontrado. No modo automático
+// um cliente com documento também corresponde, pelo nome e email, às linhas sem documento.
+func existingKeys(matchKey string, client *models.Client) []string {
+	if matchKey != importMatchAuto || client.Document == "" {
+		return []string{matchKeyValue(matchKey, client)}
+	}
+	return []string{matchKeyValue(matchKey, client), matchKeyValue(importMatchNameEmail, client)}
+}
+
+// matchKeyValue monta a chave de correspondência de um cliente, usada para detectar repetições dentro do
+// arquivo e para localizar o cliente cadastrado. Retorna "" quando o cliente não tem os dados da chave.
+func matchKeyValue(matchKey string, client *models.Client) string {
+	switch matchKey {
+	case importMatchDocument:
+		if client.Document == "" {
+			return ""
+		}
+		return "document|" + client.Document
+	case importMatchEmail:
+		if client.Email == "" {
+			return ""
+		}
+		return "email|" + strings.ToLower(client.Email)
+	case importMatchNameEmail:
+		return "name+email|" + client.Name + "|" + client.Email
+	}
+	if client.Document != "" {
+		return "name+document|" + client.Name + "|" + client.Document
+	}
+	return matchKeyValue(importMatchNameEmail, client)
+}
+
+// importMaxReportedFailures limita as linhas com problema guardadas para a resposta de uma importação real
+const importMaxReportedFailures = 10000
+
+// importCollector acumula os resultados à medida que as linhas são processadas. A lista completa só é
+// guardada em dry-run (keepRows); nas importações reais ficam apenas os totais e as linhas com problema,
+// para que a memória não cresça com o tamanho do arquivo.
+type importCollector struct {
+	keepRows          bool
+	Totals            map[string]int
+	bySheet           map[string]map[string]int
+	Rows              []importRowResult
+	Failures          []importRowResult
+	FailuresTruncated bool
+}
+
+func newImportCollector(keepRows bool) *importCollector {
+	return &importCollector{keepRows: keepRows, Totals: map[string]int{}, bySheet: map[string]map[string]int{}}
+}
+
+func (c *importCollector) add(res importRowResult) {
+	c.Totals[res.Status]++
+	if c.bySheet[res.Sheet] == nil {
+		c.bySheet[res.Sheet] = map[string]int{}
+	}
+	c.bySheet[res.Sheet][res.Status]++
+	if c.keepRows {
+		c.Rows = append(c.Rows, res)
+	}
+	if res.Status == importStatusInvalid || res.Status == importStatusError {
+		if len(c.Failures) < importMaxReportedFailures {
+			c.Failures = append(c.Failures, res)
+		} else {
+			c.FailuresTruncated = true
+		}
+	}
+}
+
+// failed indica se alguma linha impede uma importação tudo ou nada
+func (c *importCollector) failed() bool {
+	return c.Totals[importStatusInvalid]+c.Totals[importStatusError] > 0
+}
+
+// rollBack passa as linhas gravadas para rolled_back, depois que a transação foi desfeita
+func (c *importCollector) rollBack() {
+	for _, totals := range append(slices.Collect(maps.Values(c.bySheet)), c.Totals) {
+		totals[importStatusRolledBack] += totals[importStatusInserted] + totals[importStatusUpdated] + totals[importStatusContactAdded]
+		delete(totals, importStatusInserted)
+		delete(totals, importStatusUpdated)
+		delete(totals, importStatusContactAdded)
+	}
+}
+
+// sortedRows retorna os resultados guardados na ordem do arquivo
+func (c *importCollector) sortedRows() []importRowResult {
+	slices.SortFunc(c.Rows, func(a, b importRowResult) int { return a.seq - b.seq })
+	return c.Rows
+}
+
+// sheets totaliza os resultados por aba, na ordem em que as abas aparecem no arquivo
+func (c *importCollector) sheets(sheets []importSheet) []importSheetSummary {
+	summaries := make([]importSheetSummary, len(sheets))
+	for i, sheet := range sheets {
+		totals := c.bySheet[sheet.Name]
+		if totals == nil {
+			totals = map[string]int{}
+		}
+		summaries[i] = importSheetSummary{importSheet: sheet, Totals: totals}
+	}
+	return summaries
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// importPreviewDefaultTTL é a validade padrão de uma pré-visualização (IMPORT_PREVIEW_TTL)
+const importPreviewDefaultTTL = 30 * time.Minute
+
+// importPreviewTTL lê a validade das pré-visualizações de IMPORT_PREVIEW_TTL (ex.: "1h"), padrão 30 minutos
+func importPreviewTTL() time.Duration {
+	s := os.Getenv("IMPORT_PREVIEW_TTL")
+	if s == "" {
+		return importPreviewDefaultTTL
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d
+	}
+	fmt.Printf("[AVISO] IMPORT_PREVIEW_TTL inválido (%q), usando %s\n", s, importPreviewDefaultTTL)
+	return importPreviewDefaultTTL
+}
+
+// Limites padrão das pré-visualizações guardadas (IMPORT_PREVIEW_MAX e IMPORT_PREVIEW_MAX_ROWS)
+const (
+	importPreviewDefaultMax     = 20
+	importPreviewDefaultMaxRows = 100000
+)
+
+// importPreviewLimit lê um limite positivo da variável de ambiente name, ou usa o padrão
+func importPreviewLimit(name string, def int) int {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return n
+	}
+	fmt.Printf("[AVISO] %s inválido (%q), usando %d\n", name, s, def)
+	return def
+}
+
+// importPreview guarda as linhas já lidas de um dry-run, com as opções usadas, até que sejam efetivadas ou expirem
+type importPreview struct {
+	data      importData
+	options   importOptions
+	expiresAt time.Time
+}
+
+// importPreviewStore mantém em memória as pré-visualizações de importação indexadas por token. Para limitar a
+// memória, guarda no máximo maxItems pré-visualizações (as mais antigas são descartadas) de até maxRows linhas
+// cada. Os tokens se perdem quando a API reinicia e só valem na instância que fez o dry-run.
+type importPreviewStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	maxItems int
+	maxRows  int
+	items    map[string]importPreview
+}
+
+func newImportPreviewStore(ttl time.Duration, maxItems, maxRows int) *importPreviewStore {
+	return &importPreviewStore{ttl: ttl, maxItems: maxItems, maxRows: maxRows, items: map[string]importPreview{}}
+}
+
+// Save armazena os dados lidos e retorna o token e a validade da pré-visualização. Com o limite de
+// pré-visualizações atingido, descarta a mais antiga.
+func (s *importPreviewStore) Save(data importData, options importOptions) (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired()
+	for len(s.items) >= s.maxItems {
+		s.evictOldest()
+	}
+	token := uuid.New().String()
+	expiresAt := time.Now().Add(s.ttl)
+	s.items[token] = importPreview{data: data, options: options, expiresAt: expiresAt}
+	return token, expiresAt
+}
+
+// Take retorna os dados e opções do token e o invalida, para que a mesma pré-visualização não seja efetivada duas vezes
+func (s *importPreviewStore) Take(token string) (importData, importOptions, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired()
+	p, ok := s.items[token]
+	if !ok {
+		return importData{}, importOptions{}, false
+	}
+	delete(s.items, token)
+	return p.data, p.options, true
+}
+
+// purgeExpired remove as pré-visualizações vencidas (chamar com o lock adquirido)
+func (s *importPreviewStore) purgeExpired() {
+	now := time.Now()
+	for token, p := range s.items {
+		if now.After(p.expiresAt) {
+			delete(s.items, token)
+		}
+	}
+}
+
+// evictOldest descarta a pré-visualização mais próxima de vencer (chamar com o lock adquirido)
+func (s *importPreviewStore) evictOldest() {
+	oldest := ""
+	for token, p := range s.items {
+		if oldest == "" || p.expiresAt.Before(s.items[oldest].expiresAt) {
+			oldest = token
+		}
+	}
+	fmt.Printf("[AVISO] Limite de %d pré-visualizações atingido, descartando %s\n", s.maxItems, oldest)
+	delete(s.items, oldest)
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/utils"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// exportLinkTTL é a validade máxima do link de download devolvido em GET /exports/:id
+const exportLinkTTL = 15 * time.Minute
+
+type ExportJobController struct {
+	repo        *repositories.ExportJobRepository
+	worker      *ExportWorker
+	s3presigner utils.S3Presigner
+}
+
+func NewExportJobController(repo *repositories.ExportJobRepository, worker *ExportWorker, presigner utils.S3Presigner) *ExportJobController {
+	return &ExportJobController{repo: repo, worker: worker, s3presigner: presigner}
+}
+
+// CreateExportJob godoc
+// @Summary      Agenda uma exportação de clientes
+// @Description  Aceita os mesmos parâmetros de GET /clients/export (formato, colunas, opções do CSV e filtros) e processa a exportação em segundo plano.
+// @Description  Acompanhe o andamento e obtenha o link de download em GET /exports/{id}. O arquivo fica disponível por EXPORT_TTL (padrão 24h).
+// @Tags         exports
+// @Produce      json
+// @Param        format query string false "xlsx, csv ou jsonl"
+// @Param        columns query string false "Colunas separadas por vírgula, na ordem desejada"
+// @Param        delimiter query string false "Separador do CSV: ; , | ou tab (padrão ;)"
+// @Param        bom query bool false "Grava o BOM UTF-8 no CSV (padrão true)"
+// @Param        phone query string false "Telefone em qualquer formato"
+// @Param        city query string false "Cidade"
+// @Param        uf query string false "UF (sigla do estado)"
+// @Param        cep query string false "CEP"
+// @Param        importJobId query string false "Somente clientes criados ou alterados por último nessa importação"
+// @Param        tag query string false "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)"
+// @Param        withoutTag query string false "Somente clientes sem nenhuma dessas tags"
+// @Success      202 {object} models.ExportJob
+// @Failure      400 {object} map[string]interface{} "Lista de erros de validação {field, code, message}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /exports [post]
+// @Security     ApiKeyAuth
+func (c *ExportJobController) Create(ctx *gin.Context) {
+	req, ok := parseClientExportRequest(ctx)
+	if !ok {
+		return
+	}
+	job := models.ExportJob{Query: req.Query.Encode(), Format: req.Format.Name, Status: models.ExportJobPending}
+	if err := c.repo.Create(&job); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao agendar exportação"})
+		return
+	}
+	c.worker.Notify()
+	ctx.Header("Location", "/exports/"+job.ID)
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// GetExportJobByID godoc
+// @Summary      Consulta uma exportação
+// @Description  Retorna a situação (pending, running, done, failed, expired), o progresso de 0 a 1 e, quando concluída, um link temporário para download
+// @Tags         exports
+// @Produce      json
+// @Param        id path string true "ID da exportação"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /exports/{id} [get]
+// @Security     ApiKeyAuth
+func (c *ExportJobController) GetByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	job, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Exportação não encontrada"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar exportação"})
+		}
+		return
+	}
+
+	resp := gin.H{"job": job, "progress": job.Progress()}
+	if job.Status == models.ExportJobDone && job.ExpiresAt != nil {
+		// O link não vale além da validade do arquivo
+		expires := min(exportLinkTTL, time.Until(*job.ExpiresAt))
+		if expires > 0 {
+			bucket := strings.TrimSpace(strings.Trim(os.Getenv("AWS_BUCKET_NAME"), "."))
+			url, err := c.s3presigner.PresignGetObject(context.Background(), bucket, job.FileKey, expires)
+			if err != nil {
+				fmt.Printf("[ERRO] Falha ao gerar link da exportação %s: %v\n", job.ID, err)
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar link temporário para download", "details": err.Error()})
+				return
+			}
+			resp["download_url"] = url
+		}
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
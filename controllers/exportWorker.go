@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/utils"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	exportBatchSize       = 1000
+	exportDefaultTTL      = 24 * time.Hour   // validade padrão do arquivo exportado (EXPORT_TTL)
+	exportPollInterval    = 5 * time.Second  // busca jobs pendentes criados por outras instâncias
+	exportCleanupInterval = time.Hour        // remoção dos arquivos expirados
+	exportStaleAfter      = 10 * time.Minute // job em andamento sem progresso é considerado abandonado
+)
+
+// ExportWorker processa em segundo plano os jobs de exportação de clientes: grava o arquivo em disco lote a
+// lote, envia ao S3 e, depois da validade, remove o arquivo do S3.
+type ExportWorker struct {
+	jobs     *repositories.ExportJobRepository
+	clients  *repositories.ClientRepository
+	uploader utils.S3Uploader
+	deleter  utils.S3Deleter
+	ttl      time.Duration
+	wake     chan struct{}
+}
+
+// NewExportWorker cria o worker. A validade dos arquivos vem de EXPORT_TTL (ex.: "24h"), padrão 24 horas.
+func NewExportWorker(jobs *repositories.ExportJobRepository, clients *repositories.ClientRepository, uploader utils.S3Uploader, deleter utils.S3Deleter) *ExportWorker {
+	ttl := exportDefaultTTL
+	if s := os.Getenv("EXPORT_TTL"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			ttl = d
+		} else {
+			fmt.Printf("[AVISO] EXPORT_TTL inválido (%q), usando %s\n", s, exportDefaultTTL)
+		}
+	}
+	return &ExportWorker{jobs: jobs, clients: clients, uploader: uploader, deleter: deleter, ttl: ttl, wake: make(chan struct{}, 1)}
+}
+
+// Start inicia o processamento da fila e a limpeza dos arquivos expirados, até ctx ser cancelado
+func (w *ExportWorker) Start(ctx context.Context) {
+	if n, err := w.jobs.RequeueStale(time.Now().Add(-exportStaleAfter)); err != nil {
+		fmt.Printf("[ERRO] Falha ao recolocar exportações abandonadas na fila: %v\n", err)
+	} else if n > 0 {
+		fmt.Printf("[INFO] %d exportação(ões) abandonada(s) recolocada(s) na fila\n", n)
+	}
+	go w.run(ctx)
+	go w.cleanupLoop(ctx)
+}
+
+// Notify avisa o worker de que há um job novo na fila
+func (w *ExportWorker) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *ExportWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+	for {
+		for ctx.Err() == nil {
+			job, err := w.jobs.ClaimNext()
+			if err != nil {
+				fmt.Printf("[ERRO] Falha ao buscar exportação pendente: %v\n", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			w.process(ctx, job)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ExportWorker) process(ctx context.Context, job *models.ExportJob) {
+	fmt.Printf("[INFO] Exportação %s iniciada (%s)\n", job.ID, job.Format)
+	fileKey, err := w.export(ctx, job)
+	if err != nil {
+		fmt.Printf("[ERRO] Exportação %s falhou: %v\n", job.ID, err)
+		if err := w.jobs.Fail(job, err.Error()); err != nil {
+			fmt.Printf("[ERRO] Falha ao registrar erro da exportação %s: %v\n", job.ID, err)
+		}
+		return
+	}
+	if err := w.jobs.Finish(job, fileKey, time.Now().Add(w.ttl)); err != nil {
+		fmt.Printf("[ERRO] Falha ao concluir exportação %s: %v\n", job.ID, err)
+		return
+	}
+	fmt.Printf("[INFO] Exportação %s concluída: %d clientes em %s\n", job.ID, job.Processed, fileKey)
+}
+
+// export grava o arquivo em um temporário, lendo os clientes em lotes, e o envia ao S3. Retorna a chave no S3.
+func (w *ExportWorker) export(ctx context.Context, job *models.ExportJob) (string, error) {
+	q, err := url.ParseQuery(job.Query)
+	if err != nil {
+		return "", fmt.Errorf("parâmetros inválidos: %w", err)
+	}
+	req, errs := parseClientExportValues(q)
+	if errs != nil {
+		return "", errs
+	}
+	total, err := w.clients.Count(req.Filter)
+	if err != nil {
+		return "", fmt.Errorf("erro ao contar clientes: %w", err)
+	}
+	job.Total = int(total)
+	if err := w.jobs.UpdateProgress(job.ID, job.Total, 0); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp("", "export-*"+req.Format.Extension)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	cw, err := newClientExportWriter(tmp, req, w.clients)
+	if err != nil {
+		return "", err
+	}
+	err = w.clients.FindInBatches(req.Filter, exportBatchSize, func(clients []models.Client) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := cw.Write(clients); err != nil {
+			return err
+		}
+		job.Processed += len(clients)
+		return w.jobs.UpdateProgress(job.ID, max(job.Total, job.Processed), job.Processed)
+	})
+	if err != nil {
+		cw.Close()
+		return "", fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+	if err := cw.Close(); err != nil {
+		return "", fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+
+	if _, err := tmp.Seek(0, 0); err != nil {
+		return "", err
+	}
+	fileKey := fmt.Sprintf("exports/clientes_export_%s_%s%s", job.CreatedAt.Format("20060102_150405"), job.ID[:8], req.Format.Extension)
+	if _, err := w.uploader.UploadToS3(ctx, fileKey, tmp); err != nil {
+		return "", err
+	}
+	return fileKey, nil
+}
+
+func (w *ExportWorker) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(exportCleanupInterval)
+	defer ticker.Stop()
+	for {
+		w.Cleanup(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Cleanup remove do S3 os arquivos de exportação vencidos e marca os jobs como expirados
+func (w *ExportWorker) Cleanup(ctx context.Context) {
+	jobs, err := w.jobs.FindExpired(time.Now())
+	if err != nil {
+		fmt.Printf("[ERRO] Falha ao buscar exportações expiradas: %v\n", err)
+		return
+	}
+	for _, job := range jobs {
+		if err := w.deleter.DeleteFromS3(ctx, job.FileKey); err != nil {
+			fmt.Printf("[ERRO] Falha ao remover arquivo %s da exportação %s: %v\n", job.FileKey, job.ID, err)
+			continue
+		}
+		if err := w.jobs.MarkExpired(job.ID); err != nil {
+			fmt.Printf("[ERRO] Falha ao marcar exportação %s como expirada: %v\n", job.ID, err)
+		}
+	}
+	if len(jobs) > 0 {
+		fmt.Printf("[INFO] %d arquivo(s) de exportação expirado(s) processado(s)\n", len(jobs))
+	}
+}
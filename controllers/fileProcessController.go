@@ -24,21 +24,31 @@ func NewFileProcessController(repo repositories.FileProcessRepositoryInterface,
 }
 
 // GetAll godoc
-// @Summary      Lista todos os arquivos
-// @Description  Retorna todos os arquivos processados
+// @Summary      Lista os arquivos
+// @Description  Retorna uma página de arquivos processados no envelope {data, total, limit, offset, next_cursor, links}.
+// @Description  Filtros por campo (id, fileName, status, received_at) com operadores: ?status=x, ?fileName[ilike]=trecho, ?status[in]=a,b, ?received_at[gte]=2024-01-01, ?received_at[lte]=...
 // @Tags         files
 // @Produce      json
-// @Success      200  {array}   models.FileProcess
+// @Param        sort query string false "Campos separados por vírgula; prefixo - para ordem decrescente" example(-received_at)
+// @Param        limit query int false "Itens por página (padrão 50, máximo 500)"
+// @Param        offset query int false "Itens a pular"
+// @Param        cursor query string false "Cursor da próxima página (next_cursor da resposta anterior)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Lista de erros de validação {field, code, message}"
 // @Failure      401  {object}  map[string]string
 // @Router       /files [get]
 // @Security     ApiKeyAuth
 func (c *FileProcessController) GetAll(ctx *gin.Context) {
-	files, err := c.repo.GetAll()
+	q, ok := parseListQuery(ctx, repositories.FileProcessListSpec)
+	if !ok {
+		return
+	}
+	files, err := c.repo.List(q)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar arquivos"})
 		return
 	}
-	ctx.JSON(http.StatusOK, files)
+	respondList(ctx, files, q)
 }
 
 // GetByID godoc
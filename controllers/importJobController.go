@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImportJobController struct {
+	repo *repositories.ImportJobRepository
+}
+
+func NewImportJobController(repo *repositories.ImportJobRepository) *ImportJobController {
+	return &ImportJobController{repo: repo}
+}
+
+// GetAllImportJobs godoc
+// @Summary      Lista as importações de clientes
+// @Description  Retorna o histórico de importações (arquivo, quem importou, quando, opções e totais), da mais recente para a mais antiga
+// @Tags         imports
+// @Produce      json
+// @Success      200 {array} models.ImportJob
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /imports [get]
+// @Security     ApiKeyAuth
+func (c *ImportJobController) GetAll(ctx *gin.Context) {
+	jobs, err := c.repo.GetAll()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar importações"})
+		return
+	}
+	ctx.JSON(http.StatusOK, jobs)
+}
+
+// GetImportJobByID godoc
+// @Summary      Busca importação por ID
+// @Description  Retorna a importação e os clientes inseridos e atualizados por ela
+// @Tags         imports
+// @Produce      json
+// @Param        id path string true "ID da importação"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /imports/{id} [get]
+// @Security     ApiKeyAuth
+func (c *ImportJobController) GetByID(ctx *gin.Context) {
+	job, ok := c.findJob(ctx)
+	if !ok {
+		return
+	}
+	changes, err := c.repo.Changes(job.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar alterações da importação"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"job": job, "changes": changes})
+}
+
+// RollbackImportJob godoc
+// @Summary      Desfaz uma importação
+// @Description  Exclui os clientes inseridos pela importação e restaura os dados anteriores dos clientes atualizados por ela.
+// @Description  Clientes alterados depois do job (manualmente ou por outra importação) são mantidos e listados em skipped.
+// @Description  Só importações concluídas (completed) ou interrompidas pelo limite de linhas (interrupted) podem ser desfeitas.
+// @Tags         imports
+// @Produce      json
+// @Param        id path string true "ID da importação"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Importação não pode ser desfeita, ou um cliente restaurado teria o documento ou o e-mail de outro"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /imports/{id}/rollback [post]
+// @Security     ApiKeyAuth
+func (c *ImportJobController) Rollback(ctx *gin.Context) {
+	job, ok := c.findJob(ctx)
+	if !ok {
+		return
+	}
+	result, err := c.repo.Rollback(&job, requestAudit(ctx))
+	if errors.Is(err, repositories.ErrImportJobNotRollbackable) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Importação com situação '%s' não pode ser desfeita", job.Status)})
+		return
+	}
+	if respondClientConflict(ctx, err) {
+		return // um cliente restaurado teria o documento ou o e-mail de outro cadastrado depois
+	}
+	if err != nil {
+		fmt.Printf("[ERRO] Falha ao desfazer importação %s: %v\n", job.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desfazer importação"})
+		return
+	}
+	fmt.Printf("[INFO] Importação %s desfeita: %d excluídos, %d restaurados, %d mantidos\n", job.ID, result.Deleted, result.Restored, len(result.Skipped))
+	ctx.JSON(http.StatusOK, gin.H{"job": job, "deleted": result.Deleted, "restored": result.Restored, "skipped": result.Skipped})
+}
+
+// findJob carrega o job do parâmetro :id. Em caso de erro já escreve a resposta e retorna ok=false.
+func (c *ImportJobController) findJob(ctx *gin.Context) (models.ImportJob, bool) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return models.ImportJob{}, false
+	}
+	job, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Importação não encontrada"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar importação"})
+		}
+		return models.ImportJob{}, false
+	}
+	return job, true
+}
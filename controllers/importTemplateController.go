@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImportTemplateController struct {
+	repo *repositories.ImportTemplateRepository
+}
+
+func NewImportTemplateController(repo *repositories.ImportTemplateRepository) *ImportTemplateController {
+	return &ImportTemplateController{repo: repo}
+}
+
+// GetAllImportTemplates godoc
+// @Summary      Lista os templates de importação
+// @Description  Retorna todos os templates de mapeamento de colunas para importação de clientes
+// @Tags         import-templates
+// @Produce      json
+// @Success      200 {array} models.ImportTemplate
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /import-templates [get]
+// @Security     ApiKeyAuth
+func (c *ImportTemplateController) GetAll(ctx *gin.Context) {
+	templates, err := c.repo.GetAll()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar templates"})
+		return
+	}
+	ctx.JSON(http.StatusOK, templates)
+}
+
+// GetImportTemplateByID godoc
+// @Summary      Busca template de importação por ID
+// @Tags         import-templates
+// @Produce      json
+// @Param        id path string true "ID do template"
+// @Success      200 {object} models.ImportTemplate
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /import-templates/{id} [get]
+// @Security     ApiKeyAuth
+func (c *ImportTemplateController) GetByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	t, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Template não encontrado"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar template"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, t)
+}
+
+// CreateImportTemplate godoc
+// @Summary      Cria um template de importação
+// @Description  Cria um mapeamento de colunas da planilha de origem para campos do cliente (name, email, phone, address, document, person_type)
+// @Tags         import-templates
+// @Accept       json
+// @Produce      json
+// @Param        template body models.ImportTemplate true "Template de importação"
+// @Success      201 {object} models.ImportTemplate
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /import-templates [post]
+// @Security     ApiKeyAuth
+func (c *ImportTemplateController) Create(ctx *gin.Context) {
+	var t models.ImportTemplate
+	if err := ctx.ShouldBindJSON(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	if err := validateImportTemplate(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t.ID = uuid.New().String()
+	if err := c.repo.Create(&t); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar template"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, t)
+}
+
+// UpdateImportTemplate godoc
+// @Summary      Atualiza um template de importação
+// @Tags         import-templates
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID do template"
+// @Param        template body models.ImportTemplate true "Template de importação"
+// @Success      200 {object} models.ImportTemplate
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /import-templates/{id} [put]
+// @Security     ApiKeyAuth
+func (c *ImportTemplateController) Update(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	var t models.ImportTemplate
+	if err := ctx.ShouldBindJSON(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	if err := validateImportTemplate(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := c.repo.GetByID(id); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Template não encontrado"})
+		return
+	}
+	t.ID = id
+	if err := c.repo.Update(&t); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar template"})
+		return
+	}
+	ctx.JSON(http.StatusOK, t)
+}
+
+// DeleteImportTemplate godoc
+// @Summary      Remove um template de importação
+// @Tags         import-templates
+// @Produce      json
+// @Param        id path string true "ID do template"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /import-templates/{id} [delete]
+// @Security     ApiKeyAuth
+func (c *ImportTemplateController) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	if err := c.repo.Delete(id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar template"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Template deletado com sucesso"})
+}
+
+// validateImportTemplate garante que o template tem nome e só aponta para campos conhecidos do cliente
+func validateImportTemplate(t *models.ImportTemplate) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("O nome do template é obrigatório")
+	}
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("O template deve mapear ao menos uma coluna")
+	}
+	for src, field := range t.Columns {
+		if !clientImportFields[field] {
+			return fmt.Errorf("Campo de destino inválido para a coluna '%s': %s", src, field)
+		}
+	}
+	return nil
+}
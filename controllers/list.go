@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"minha-api/repositories"
+	"minha-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listEnvelope é a resposta das listagens paginadas
+type listEnvelope[T any] struct {
+	Data       []T       `json:"data"`
+	Total      int64     `json:"total"` // itens que atendem aos filtros, em todas as páginas
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Links      listLinks `json:"links"`
+}
+
+// listLinks são as URLs da página atual e das vizinhas, com os mesmos filtros e ordenação
+type listLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// parseListQuery lê a consulta de listagem da query string. Em caso de erro já escreve a resposta e retorna ok=false.
+func parseListQuery(ctx *gin.Context, spec utils.ListSpec) (utils.ListQuery, bool) {
+	q, errs := utils.ParseListQuery(ctx.Request.URL.Query(), spec)
+	if errs != nil {
+		respondValidationErrors(ctx, errs)
+		return q, false
+	}
+	return q, true
+}
+
+// respondList responde 200 com a página no envelope {data, total, limit, offset, next_cursor, links}.
+// Quem pediu a página por cursor recebe o link da próxima também por cursor; os demais, por offset.
+func respondList[T any](ctx *gin.Context, result repositories.ListResult[T], q utils.ListQuery) {
+	if result.Items == nil {
+		result.Items = []T{}
+	}
+	query := ctx.Request.URL.Query()
+	link := func(set map[string]string) string {
+		values := ctx.Request.URL.Query()
+		for k, v := range set {
+			if v == "" {
+				values.Del(k)
+			} else {
+				values.Set(k, v)
+			}
+		}
+		if len(values) == 0 {
+			return ctx.Request.URL.Path
+		}
+		return ctx.Request.URL.Path + "?" + values.Encode()
+	}
+
+	links := listLinks{Self: link(nil)}
+	limit := strconv.Itoa(q.Limit)
+	if query.Has(utils.ListParamCursor) {
+		if result.NextCursor != "" {
+			links.Next = link(map[string]string{utils.ListParamCursor: result.NextCursor, utils.ListParamLimit: limit})
+		}
+	} else {
+		if int64(q.Offset+len(result.Items)) < result.Total {
+			links.Next = link(map[string]string{utils.ListParamOffset: strconv.Itoa(q.Offset + q.Limit), utils.ListParamLimit: limit})
+		}
+		if q.Offset > 0 {
+			links.Prev = link(map[string]string{utils.ListParamOffset: strconv.Itoa(max(q.Offset-q.Limit, 0)), utils.ListParamLimit: limit})
+		}
+	}
+
+	ctx.JSON(http.StatusOK, listEnvelope[T]{
+		Data:       result.Items,
+		Total:      result.Total,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+		NextCursor: result.NextCursor,
+		Links:      links,
+	})
+}
@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"fmt"
+	"maps"
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/utils"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SegmentController struct {
+	repo    *repositories.SegmentRepository
+	clients *repositories.ClientRepository
+}
+
+func NewSegmentController(repo *repositories.SegmentRepository, clients *repositories.ClientRepository) *SegmentController {
+	return &SegmentController{repo: repo, clients: clients}
+}
+
+// GetAllSegments godoc
+// @Summary      Lista os segmentos de clientes
+// @Tags         segments
+// @Produce      json
+// @Success      200 {array} models.Segment
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /segments [get]
+// @Security     ApiKeyAuth
+func (c *SegmentController) GetAll(ctx *gin.Context) {
+	segments, err := c.repo.GetAll()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar segmentos"})
+		return
+	}
+	if segments == nil {
+		segments = []models.Segment{}
+	}
+	ctx.JSON(http.StatusOK, segments)
+}
+
+// GetSegmentByID godoc
+// @Summary      Busca segmento por ID
+// @Tags         segments
+// @Produce      json
+// @Param        id path string true "ID do segmento"
+// @Success      200 {object} models.Segment
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /segments/{id} [get]
+// @Security     ApiKeyAuth
+func (c *SegmentController) GetByID(ctx *gin.Context) {
+	s, ok := c.find(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, s)
+}
+
+// CreateSegment godoc
+// @Summary      Cria um segmento de clientes
+// @Description  Um segmento guarda um filtro, não uma lista de clientes: os membros são calculados a cada consulta em GET /segments/{id}/clients.
+// @Description  O filtro usa a sintaxe da query string de GET /clients, ex.: "tag=VIP&withoutTag=inadimplente&person_type=PJ&address_parts.uf[in]=RS,SC,PR".
+// @Tags         segments
+// @Accept       json
+// @Produce      json
+// @Param        segment body models.Segment true "Segmento"
+// @Success      201 {object} models.Segment
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      409 {object} map[string]string "Já existe um segmento com esse nome"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /segments [post]
+// @Security     ApiKeyAuth
+func (c *SegmentController) Create(ctx *gin.Context) {
+	var s models.Segment
+	if err := ctx.ShouldBindJSON(&s); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	if !c.validate(ctx, &s, "") {
+		return
+	}
+	s.ID = uuid.New().String()
+	if err := c.repo.Create(&s); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar segmento"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, s)
+}
+
+// UpdateSegment godoc
+// @Summary      Atualiza um segmento de clientes
+// @Tags         segments
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID do segmento"
+// @Param        segment body models.Segment true "Segmento"
+// @Success      200 {object} models.Segment
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Já existe um segmento com esse nome"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /segments/{id} [put]
+// @Security     ApiKeyAuth
+func (c *SegmentController) Update(ctx *gin.Context) {
+	current, ok := c.find(ctx)
+	if !ok {
+		return
+	}
+	var s models.Segment
+	if err := ctx.ShouldBindJSON(&s); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	if !c.validate(ctx, &s, current.ID) {
+		return
+	}
+	s.ID, s.CreatedAt = current.ID, current.CreatedAt
+	if err := c.repo.Update(&s); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar segmento"})
+		return
+	}
+	ctx.JSON(http.StatusOK, s)
+}
+
+// DeleteSegment godoc
+// @Summary      Remove um segmento de clientes
+// @Description  Os clientes não são alterados
+// @Tags         segments
+// @Produce      json
+// @Param        id path string true "ID do segmento"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /segments/{id} [delete]
+// @Security     ApiKeyAuth
+func (c *SegmentController) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	if err := c.repo.Delete(id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar segmento"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Segmento deletado com sucesso"})
+}
+
+// SegmentClients godoc
+// @Summary      Lista os clientes de um segmento
+// @Description  Avalia o filtro do segmento agora e retorna uma página dos clientes no envelope {data, total, limit, offset, next_cursor, links}, como GET /clients.
+// @Description  Ordenação e paginação vêm da query string; filtros extras na query string restringem ainda mais o segmento.
+// @Tags         segments
+// @Produce      json
+// @Param        id path string true "ID do segmento"
+// @Param        sort query string false "Campos separados por vírgula; prefixo - para ordem decrescente"
+// @Param        limit query int false "Itens por página (padrão 50, máximo 500)"
+// @Param        offset query int false "Itens a pular"
+// @Param        cursor query string false "Cursor da próxima página (next_cursor da resposta anterior)"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]interface{} "Lista de erros de validação {field, code, message}"
+// @Failure      404 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /segments/{id}/clients [get]
+// @Security     ApiKeyAuth
+func (c *SegmentController) Clients(ctx *gin.Context) {
+	s, ok := c.find(ctx)
+	if !ok {
+		return
+	}
+	values, errs := parseSegmentFilter(s.Filter)
+	if errs != nil {
+		// O filtro foi validado ao salvar; só falha se os campos de cliente mudaram desde então
+		respondValidationErrors(ctx, errs)
+		return
+	}
+	// Os filtros do segmento prevalecem sobre os da query string com o mesmo nome
+	for key, v := range ctx.Request.URL.Query() {
+		if !values.Has(key) {
+			values[key] = v
+		}
+	}
+	q, errs := utils.ParseListQuery(values, repositories.ClientListSpec)
+	if errs != nil {
+		respondValidationErrors(ctx, errs)
+		return
+	}
+	clients, err := c.clients.List(clientFilterFromValues(values), q)
+	if err != nil {
+		fmt.Printf("[ERRO] Falha ao avaliar segmento %s: %v\n", s.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+		return
+	}
+	respondList(ctx, clients, q)
+}
+
+// parseSegmentFilter interpreta a expressão de filtro de um segmento, que usa a sintaxe da query string de
+// GET /clients. Só são aceitos filtros: ordenação, paginação e parâmetros desconhecidos são recusados.
+func parseSegmentFilter(expr string) (url.Values, utils.ValidationErrors) {
+	values, err := url.ParseQuery(expr)
+	if err != nil {
+		return nil, utils.ValidationErrors{{Field: "filter", Code: utils.CodeInvalidValue, Message: "Expressão inválida: use a sintaxe da query string de GET /clients, ex.: tag=VIP&person_type=PJ"}}
+	}
+	var v utils.Validator
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		switch {
+		case slices.Contains([]string{utils.ListParamSort, utils.ListParamLimit, utils.ListParamOffset, utils.ListParamCursor}, key):
+			v.Add("filter", utils.CodeInvalidValue, fmt.Sprintf("%s não faz parte do filtro: ordenação e paginação vêm da consulta", key))
+		case strings.Contains(key, "["):
+			// campo[operador]: validado por ParseListQuery
+		case !slices.Contains(clientFilterParams, key):
+			if _, ok := repositories.ClientListSpec.Fields[key]; !ok {
+				v.Add("filter", utils.CodeInvalidValue, fmt.Sprintf("Campo não pode ser filtrado: %s", key))
+			}
+		}
+	}
+	if _, errs := utils.ParseListQuery(values, repositories.ClientListSpec); errs != nil {
+		for _, e := range errs {
+			v.Add("filter", e.Code, e.Field+": "+e.Message)
+		}
+	}
+	return values, v.Errors()
+}
+
+// validate confere o segmento, a expressão do filtro e a unicidade do nome (exceptID é o próprio segmento).
+// Em caso de erro já escreve a resposta e retorna false.
+func (c *SegmentController) validate(ctx *gin.Context, s *models.Segment, exceptID string) bool {
+	errs := s.Validate()
+	if s.Filter != "" {
+		_, filterErrs := parseSegmentFilter(s.Filter)
+		errs = append(errs, filterErrs...)
+	}
+	if errs != nil {
+		respondValidationErrors(ctx, errs)
+		return false
+	}
+	exists, err := c.repo.ExistsByName(s.Name, exceptID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar segmento"})
+		return false
+	}
+	if exists {
+		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Já existe o segmento %s", s.Name)})
+		return false
+	}
+	return true
+}
+
+// find carrega o segmento do caminho. Em caso de erro já escreve a resposta e retorna ok=false.
+func (c *SegmentController) find(ctx *gin.Context) (models.Segment, bool) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return models.Segment{}, false
+	}
+	s, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Segmento não encontrado"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar segmento"})
+		}
+		return models.Segment{}, false
+	}
+	return s, true
+}
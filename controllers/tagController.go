@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"fmt"
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TagController struct {
+	repo *repositories.TagRepository
+}
+
+func NewTagController(repo *repositories.TagRepository) *TagController {
+	return &TagController{repo: repo}
+}
+
+// maxBulkTagClients limita os clientes de uma marcação em lote
+const maxBulkTagClients = 1000
+
+// clientTagsRequest é o corpo de POST /clients/tags
+type clientTagsRequest struct {
+	ClientIDs []string `json:"client_ids"`
+	Add       []string `json:"add"`    // nomes das tags a aplicar; as inexistentes são criadas
+	Remove    []string `json:"remove"` // nomes das tags a retirar
+}
+
+// GetAllTags godoc
+// @Summary      Lista as tags
+// @Description  Retorna as tags em ordem alfabética, com a quantidade de clientes de cada uma
+// @Tags         tags
+// @Produce      json
+// @Success      200 {array} repositories.TagWithCount
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /tags [get]
+// @Security     ApiKeyAuth
+func (c *TagController) GetAll(ctx *gin.Context) {
+	tags, err := c.repo.GetAll()
+	if err != nil {
+		fmt.Println("[ERRO] Falha ao buscar tags:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar tags"})
+		return
+	}
+	if tags == nil {
+		tags = []repositories.TagWithCount{}
+	}
+	ctx.JSON(http.StatusOK, tags)
+}
+
+// CreateTag godoc
+// @Summary      Cria uma tag
+// @Description  Cria uma tag sem clientes. Tags também são criadas automaticamente ao marcar clientes em POST /clients/tags.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Param        tag body models.Tag true "Tag"
+// @Success      201 {object} models.Tag
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      409 {object} map[string]string "Já existe uma tag com esse nome"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /tags [post]
+// @Security     ApiKeyAuth
+func (c *TagController) Create(ctx *gin.Context) {
+	var t models.Tag
+	if err := ctx.ShouldBindJSON(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	if !c.validate(ctx, &t, "") {
+		return
+	}
+	t.ID = uuid.New().String()
+	if err := c.repo.Create(&t); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar tag"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, t)
+}
+
+// UpdateTag godoc
+// @Summary      Renomeia uma tag
+// @Description  Os clientes marcados continuam com a tag, agora com o novo nome
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID da tag"
+// @Param        tag body models.Tag true "Tag"
+// @Success      200 {object} models.Tag
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string "Já existe uma tag com esse nome"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /tags/{id} [put]
+// @Security     ApiKeyAuth
+func (c *TagController) Update(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	var t models.Tag
+	if err := ctx.ShouldBindJSON(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	current, err := c.repo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Tag não encontrada"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar tag"})
+		}
+		return
+	}
+	if !c.validate(ctx, &t, id) {
+		return
+	}
+	t.ID, t.CreatedAt = id, current.CreatedAt
+	if err := c.repo.Update(&t); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar tag"})
+		return
+	}
+	ctx.JSON(http.StatusOK, t)
+}
+
+// DeleteTag godoc
+// @Summary      Remove uma tag
+// @Description  A tag é retirada de todos os clientes
+// @Tags         tags
+// @Produce      json
+// @Param        id path string true "ID da tag"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /tags/{id} [delete]
+// @Security     ApiKeyAuth
+func (c *TagController) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido. Use um UUID válido."})
+		return
+	}
+	if err := c.repo.Delete(id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar tag"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tag deletada com sucesso"})
+}
+
+// UpdateClientTags godoc
+// @Summary      Marca e desmarca clientes com tags, em lote
+// @Description  Aplica as tags de add e retira as de remove de todos os clientes de client_ids (até 1000), em uma transação.
+// @Description  Tags de add que ainda não existem são criadas. IDs de clientes inexistentes são ignorados e listados em not_found.
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Param        body body clientTagsRequest true "Clientes e tags"
+// @Success      200 {object} repositories.ClientTagsResult
+// @Failure      400 {object} map[string]interface{} "JSON inválido ou lista de erros de validação {field, code, message}"
+// @Failure      401 {object} map[string]string "API key inválida ou ausente"
+// @Router       /clients/tags [post]
+// @Security     ApiKeyAuth
+func (c *TagController) UpdateClients(ctx *gin.Context) {
+	var req clientTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	if errs := req.validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
+		return
+	}
+	result, err := c.repo.UpdateClientTags(req.ClientIDs, req.Add, req.Remove)
+	if err != nil {
+		fmt.Println("[ERRO] Falha ao atualizar tags de clientes:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar tags dos clientes"})
+		return
+	}
+	fmt.Printf("[INFO] Tags em lote: %d cliente(s), %d associação(ões) criada(s), %d removida(s)\n", len(req.ClientIDs)-len(result.NotFound), result.Added, result.Removed)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// validate normaliza os nomes das tags e confere IDs e limites
+func (r *clientTagsRequest) validate() utils.ValidationErrors {
+	v := &utils.Validator{}
+	switch {
+	case len(r.ClientIDs) == 0:
+		v.Add("client_ids", utils.CodeRequired, "Informe ao menos um cliente")
+	case len(r.ClientIDs) > maxBulkTagClients:
+		v.Add("client_ids", utils.CodeInvalidValue, fmt.Sprintf("Informe no máximo %d clientes por vez", maxBulkTagClients))
+	}
+	for i, id := range r.ClientIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			v.Add(fmt.Sprintf("client_ids[%d]", i), utils.CodeInvalidValue, "ID inválido. Use um UUID válido.")
+		}
+	}
+	if len(r.Add) == 0 && len(r.Remove) == 0 {
+		v.Add("add", utils.CodeRequired, "Informe tags em add ou remove")
+	}
+	r.Add = normalizeTagNames(v, "add", r.Add)
+	r.Remove = normalizeTagNames(v, "remove", r.Remove)
+	for _, name := range r.Add {
+		for _, other := range r.Remove {
+			if strings.EqualFold(name, other) {
+				v.Add("remove", utils.CodeInvalidValue, fmt.Sprintf("A tag %s está em add e em remove", name))
+			}
+		}
+	}
+	return v.Errors()
+}
+
+// normalizeTagNames valida e normaliza os nomes, descartando repetidos
+func normalizeTagNames(v *utils.Validator, field string, names []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for i, name := range names {
+		t := models.Tag{Name: name}
+		for _, e := range t.Validate() {
+			v.Add(fmt.Sprintf("%s[%d]", field, i), e.Code, e.Message)
+		}
+		if t.Name == "" || seen[strings.ToLower(t.Name)] {
+			continue
+		}
+		seen[strings.ToLower(t.Name)] = true
+		out = append(out, t.Name)
+	}
+	return out
+}
+
+// validate normaliza e valida a tag e garante que o nome não é usado por outra (exceptID).
+// Em caso de erro já escreve a resposta e retorna false.
+func (c *TagController) validate(ctx *gin.Context, t *models.Tag, exceptID string) bool {
+	if errs := t.Validate(); errs != nil {
+		respondValidationErrors(ctx, errs)
+		return false
+	}
+	existing, err := c.repo.FindByName(t.Name)
+	if err != nil && !strings.Contains(err.Error(), "record not found") {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar tag"})
+		return false
+	}
+	if existing != nil && existing.ID != exceptID {
+		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Já existe a tag %s", existing.Name)})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"errors"
+	"minha-api/repositories"
+	"minha-api/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondValidationErrors responde 400 com a lista de erros de validação no formato {field, code, message}
+func respondValidationErrors(ctx *gin.Context, errs utils.ValidationErrors) {
+	ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "errors": errs})
+}
+
+// respondClientConflict responde 409 com o campo em conflito e o cliente que já o usa, se err for um
+// *repositories.ClientConflictError. Retorna false, sem escrever a resposta, para qualquer outro erro.
+func respondClientConflict(ctx *gin.Context, err error) bool {
+	var conflict *repositories.ClientConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	ctx.JSON(http.StatusConflict, gin.H{"error": conflict.Error(), "field": conflict.Field, "client_id": conflict.ClientID})
+	return true
+}
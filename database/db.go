@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"minha-api/models"
+	"minha-api/utils"
 	"os"
 
 	"gorm.io/driver/postgres"
@@ -23,6 +24,85 @@ func ConnectDatabase() {
 		fmt.Println("Erro ao conectar no banco com GORM:", err)
 		panic(err)
 	}
-	// Migração automática
-	DB.AutoMigrate(&models.Client{})
+	if err := Migrate(DB); err != nil {
+		fmt.Println("Erro ao aplicar migrações:", err)
+		panic(err)
+	}
+	backfillClientPhones()
+	backfillClientAddresses()
+}
+
+// Migrate cria e atualiza as tabelas dos modelos e aplica as migrações versionadas (extensões, funções e
+// índices que o AutoMigrate não cria)
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.Client{}, &models.ImportTemplate{}, &models.ImportJob{}, &models.ImportJobChange{}, &models.ClientAlias{}, &models.ExportJob{}, &models.ClientContact{}, &models.Tag{}, &models.Segment{}, &models.ClientRevision{}); err != nil {
+		return err
+	}
+	return RunMigrations(db)
+}
+
+// backfillClientDocuments copia para o documento normalizado o CNPJ em texto livre dos clientes antigos,
+// com o tipo de pessoa detectado. Valores que não são CPF/CNPJ válidos ficam em cnpj para revisão manual.
+func backfillClientDocuments(tx *gorm.DB) error {
+	var clients []models.Client
+	err := tx.Unscoped().Select("id", "cnpj").
+		Where("(document IS NULL OR document = '') AND cnpj <> ''").Find(&clients).Error
+	if err != nil {
+		return err
+	}
+	for _, c := range clients {
+		doc, err := utils.ParseDocument(c.CNPJ)
+		if err != nil {
+			fmt.Printf("[AVISO] Cliente %s: CNPJ %q não preenchido no documento (%v), revise manualmente\n", c.ID, c.CNPJ, err)
+			continue
+		}
+		cnpj := ""
+		if doc.Type == utils.DocumentTypeCNPJ {
+			cnpj = doc.Digits
+		}
+		// Em savepoint, para que a falha de um cliente não aborte a transação da migração
+		err = tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Unscoped().Model(&models.Client{}).Where("id = ?", c.ID).
+				Updates(map[string]interface{}{"document": doc.Digits, "person_type": doc.PersonType(), "cnpj": cnpj}).Error
+		})
+		if err != nil {
+			fmt.Printf("[ERRO] Cliente %s: falha ao preencher o documento a partir do CNPJ: %v\n", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// backfillClientPhones preenche o telefone em E.164 dos clientes cadastrados antes da normalização
+func backfillClientPhones() {
+	var clients []models.Client
+	DB.Where("phone <> '' AND (phone_e164 IS NULL OR phone_e164 = '')").Find(&clients)
+	for _, c := range clients {
+		c.NormalizePhone()
+		if c.PhoneE164 == "" {
+			continue
+		}
+		DB.Model(&models.Client{}).Where("id = ?", c.ID).
+			Updates(map[string]interface{}{"phone_e164": c.PhoneE164, "phone_type": c.PhoneType})
+	}
+}
+
+// backfillClientAddresses separa em partes os endereços em texto livre cadastrados antes do endereço estruturado
+func backfillClientAddresses() {
+	var clients []models.Client
+	DB.Where("address <> '' AND (address_street IS NULL OR address_street = '')").Find(&clients)
+	for _, c := range clients {
+		parts := models.ParseAddress(c.Address)
+		if parts.IsEmpty() {
+			continue
+		}
+		DB.Model(&models.Client{}).Where("id = ?", c.ID).Updates(map[string]interface{}{
+			"address_street":       parts.Street,
+			"address_number":       parts.Number,
+			"address_complement":   parts.Complement,
+			"address_neighborhood": parts.Neighborhood,
+			"address_city":         parts.City,
+			"address_uf":           parts.UF,
+			"address_cep":          parts.CEP,
+		})
+	}
 }
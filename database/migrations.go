@@ -0,0 +1,136 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// migration é uma alteração de esquema que o AutoMigrate não faz (extensões, funções, índices por expressão).
+// Cada migração roda uma única vez, em transação, e fica registrada em schema_migrations.
+type migration struct {
+	Version    string
+	Statements []string
+	// Run, quando definido, roda depois de Statements, para o que precisa de código Go
+	Run func(tx *gorm.DB) error
+}
+
+// errMigrationPending indica que a migração não pode ser aplicada por causa dos dados atuais. Ela não é
+// registrada e volta a ser tentada na próxima inicialização; as demais seguem normalmente e a API sobe.
+var errMigrationPending = errors.New("migração adiada")
+
+// clientUniqueIndex cria um índice único parcial em clients, se não houver clientes ativos repetidos na chave.
+// Com repetidos, a migração fica pendente até que sejam mesclados (GET /clients/duplicates, POST /clients/merge).
+func clientUniqueIndex(version, index, column, key, label string) migration {
+	return migration{
+		Version: version,
+		Run: func(tx *gorm.DB) error {
+			var groups int64
+			err := tx.Raw(`SELECT count(*) FROM (SELECT 1 FROM clients WHERE deleted_at IS NULL AND ` + column + ` <> ''
+				GROUP BY ` + key + ` HAVING count(*) > 1) d`).Scan(&groups).Error
+			if err != nil {
+				return err
+			}
+			if groups > 0 {
+				return fmt.Errorf("%w: %d grupo(s) de clientes ativos com o mesmo %s; mescle-os (GET /clients/duplicates, POST /clients/merge) e reinicie a API",
+					errMigrationPending, groups, label)
+			}
+			return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS ` + index + ` ON clients (` + key + `)
+				WHERE deleted_at IS NULL AND ` + column + ` <> ''`).Error
+		},
+	}
+}
+
+// migrations lista as migrações em ordem de aplicação. Nunca altere uma migração já publicada: crie outra.
+var migrations = []migration{
+	{
+		Version: "20261019_01_client_search",
+		Statements: []string{
+			`CREATE EXTENSION IF NOT EXISTS unaccent`,
+			`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+			// unaccent() não é IMMUTABLE e por isso não pode ser usada em índices; o dicionário fixo torna o resultado estável
+			`CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+				LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+				AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$`,
+			`CREATE INDEX IF NOT EXISTS idx_clients_search_tsv ON clients
+				USING gin (to_tsvector('simple', immutable_unaccent(lower(coalesce(name, '') || ' ' || coalesce(email, '')))))`,
+			`CREATE INDEX IF NOT EXISTS idx_clients_name_trgm ON clients USING gin (immutable_unaccent(lower(name)) gin_trgm_ops)`,
+			`CREATE INDEX IF NOT EXISTS idx_clients_email_trgm ON clients USING gin (lower(email) gin_trgm_ops)`,
+			`CREATE INDEX IF NOT EXISTS idx_clients_phone_trgm ON clients USING gin (phone_e164 gin_trgm_ops)`,
+			`CREATE INDEX IF NOT EXISTS idx_clients_document_trgm ON clients USING gin (document gin_trgm_ops)`,
+		},
+	},
+	{
+		Version: "20261019_02_tags",
+		Statements: []string{
+			// "VIP" e "vip" são a mesma tag
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_lower ON tags (lower(name))`,
+			// A chave primária de client_tags começa por client_id; a busca de clientes por tag parte de tag_id
+			`CREATE INDEX IF NOT EXISTS idx_client_tags_tag_id ON client_tags (tag_id)`,
+		},
+	},
+	{
+		Version: "20261019_03_client_document_backfill",
+		// Antes dos índices únicos, para que os duplicados entre os clientes antigos também sejam encontrados
+		Run: backfillClientDocuments,
+	},
+	// Índices parciais: clientes excluídos (exclusão lógica) e campos vazios não contam. Os nomes são usados em
+	// repositories.clientUniqueIndexes
+	clientUniqueIndex("20261019_04_client_document_unique", "idx_clients_document_unique", "document", "document", "documento"),
+	clientUniqueIndex("20261019_05_client_email_unique", "idx_clients_email_unique", "email", "lower(email)", "e-mail"),
+}
+
+// migrationLockID identifica o advisory lock que impede duas instâncias da API de migrarem ao mesmo tempo
+const migrationLockID = 7254001
+
+// RunMigrations aplica as migrações ainda não registradas em schema_migrations
+func RunMigrations(db *gorm.DB) error {
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version text PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`).Error; err != nil {
+		return fmt.Errorf("erro ao criar schema_migrations: %w", err)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		// O lock é liberado junto com a transação
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+		var applied []string
+		if err := tx.Raw("SELECT version FROM schema_migrations").Scan(&applied).Error; err != nil {
+			return err
+		}
+		done := map[string]bool{}
+		for _, v := range applied {
+			done[v] = true
+		}
+		for _, m := range migrations {
+			if done[m.Version] {
+				continue
+			}
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				for _, stmt := range m.Statements {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				if m.Run != nil {
+					if err := m.Run(tx); err != nil {
+						return err
+					}
+				}
+				return tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", m.Version).Error
+			})
+			if errors.Is(err, errMigrationPending) {
+				fmt.Printf("[ERRO] Migração %s não aplicada: %v\n", m.Version, err)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("erro na migração %s: %w", m.Version, err)
+			}
+			fmt.Println("[INFO] Migração aplicada:", m.Version)
+		}
+		return nil
+	})
+}
@@ -22,22 +22,54 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retorna todos os livros cadastrados",
+                "description": "Retorna uma página de livros no envelope {data, total, limit, offset, next_cursor, links}.\nFiltros por campo (id, title, author, created_at) com operadores: ?title=x, ?title[ilike]=trecho, ?id[in]=a,b, ?created_at[gte]=2024-01-01, ?created_at[lte]=...",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "books"
                 ],
-                "summary": "Lista todos os livros",
+                "summary": "Lista os livros",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "-created_at,title",
+                        "description": "Campos separados por vírgula; prefixo - para ordem decrescente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens a pular",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor da próxima página (next_cursor da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Book"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -297,27 +329,120 @@ const docTemplate = `{
         },
         "/clients": {
             "get": {
-                "description": "Retorna todos os clientes cadastrados",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna uma página de clientes no envelope {data, total, limit, offset, next_cursor, links}. O filtro por telefone aceita qualquer formato (\"(11) 98765-4321\", \"+55 11 98765-4321\", \"11987654321\") ou apenas parte dos dígitos.\nTambém aceita filtros por campo (id, name, email, document, person_type, phone_type, address_parts.street, address_parts.city, address_parts.uf, address_parts.cep)\ncom operadores: ?name=x, ?name[ilike]=trecho, ?person_type[in]=PF,PJ, ?name[gte]=A, ?name[lte]=M",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "clients"
                 ],
-                "summary": "Lista todos os clientes",
+                "summary": "Lista os clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Telefone em qualquer formato",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cidade",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UF (sigla do estado)",
+                        "name": "uf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CEP",
+                        "name": "cep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes criados ou alterados por último nessa importação",
+                        "name": "importJobId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes sem nenhuma dessas tags",
+                        "name": "withoutTag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "address_parts.uf,name",
+                        "description": "Campos separados por vírgula; prefixo - para ordem decrescente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens a pular",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor da próxima página (next_cursor da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Client"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Cria um cliente a partir de um JSON",
                 "consumes": [
                     "application/json"
@@ -348,7 +473,23 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Documento ou e-mail já usado por outro cliente: {error, field, client_id}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -359,34 +500,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/clients/upload": {
-            "post": {
-                "description": "Recebe um arquivo .xls, lê os dados e cadastra clientes no banco",
-                "consumes": [
-                    "multipart/form-data"
+        "/clients/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Compara os clientes por nome (sem acentos, pontuação e sufixos como LTDA, ME, S/A), documento, email e telefone\ne retorna os pares com nota a partir de minScore, da maior para a menor nota. Os candidatos são gerados no banco:\nclientes com documento, email ou telefone iguais e, com minScore até 0.5, também com nomes parecidos (pg_trgm).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "clients"
                 ],
-                "summary": "Upload de clientes via arquivo Excel",
+                "summary": "Lista prováveis clientes duplicados",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Arquivo de clientes (.xls)",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "type": "number",
+                        "description": "Nota mínima, de 0 a 1 (padrão 0.6)",
+                        "name": "minScore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de pares retornados (padrão 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ClientDuplicate"
+                            }
                         }
                     },
                     "400": {
@@ -397,38 +547,147 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/clients/{id}": {
+        "/clients/export": {
             "get": {
-                "description": "Retorna um cliente pelo ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gera o arquivo com os clientes que atendem aos mesmos filtros de GET /clients. Com delivery=s3 o arquivo é salvo no S3 e a resposta traz um link temporário para download;\ncom delivery=stream o arquivo é enviado direto na resposta, como anexo, à medida que é gerado. Sem delivery vale EXPORT_DELIVERY (padrão s3).\nO formato vem de ?format= ou, na falta dele, do cabeçalho Accept (padrão XLSX). Em columns escolha as colunas e sua ordem, pelas chaves:\nid, name, email, phone, address, cnpj, document, person_type, phone_e164, address_parts.street, address_parts.number, address_parts.complement,\naddress_parts.neighborhood, address_parts.city, address_parts.uf, address_parts.cep. Sem columns, todas são exportadas.\nAs colunas de contato (contact.type, contact.name, contact.email, contact.phone, contact.primary) geram uma linha por contato, com os dados\ndo cliente repetidos e as linhas do mesmo cliente juntas; clientes sem contato saem em uma linha com as colunas de contato vazias.\ncontacts=rows acrescenta todas as colunas de contato às escolhidas.\nO CSV usa por padrão separador \";\" e BOM UTF-8, como o Excel em português espera.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "clients"
                 ],
-                "summary": "Busca cliente por ID",
+                "summary": "Exporta clientes em XLSX, CSV ou JSON Lines",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do cliente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "xlsx, csv ou jsonl",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "s3 ou stream",
+                        "name": "delivery",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name,email,address_parts.city",
+                        "description": "Colunas separadas por vírgula, na ordem desejada",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Separador do CSV: ; , | ou tab (padrão ;)",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Grava o BOM UTF-8 no CSV (padrão true)",
+                        "name": "bom",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "rows"
+                        ],
+                        "type": "string",
+                        "description": "rows: uma linha por contato do cliente",
+                        "name": "contacts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Telefone em qualquer formato",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cidade",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UF (sigla do estado)",
+                        "name": "uf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CEP",
+                        "name": "cep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes criados ou alterados por último nessa importação",
+                        "name": "importJobId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes sem nenhuma dessas tags",
+                        "name": "withoutTag",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Exemplo de resposta: {\\\"download_url\\\":\\\"https://bucket.s3.amazonaws.com/clientes_export_20250703_153000.xlsx\\\"}",
                         "schema": {
-                            "$ref": "#/definitions/models.Client"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Formato não suportado ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro ao buscar clientes, gerar o arquivo ou enviar para S3",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -437,9 +696,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Atualiza os dados de um cliente pelo ID",
+            }
+        },
+        "/clients/merge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Combina remove_id em keep_id. Em fields escolha, por campo, de qual cliente vem o valor (\"keep\" ou \"remove\");\nsem escolha vale o de keep_id, ou o de remove_id quando keep_id não tem o campo preenchido. O endereço é escolhido inteiro pelo campo address.\nO cliente removido é excluído e seu ID passa a ser um alias: GET /clients/{remove_id} retorna o cliente mesclado.",
                 "consumes": [
                     "application/json"
                 ],
@@ -449,22 +715,15 @@ const docTemplate = `{
                 "tags": [
                     "clients"
                 ],
-                "summary": "Atualiza um cliente",
+                "summary": "Mescla dois clientes",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID do cliente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Dados do cliente",
-                        "name": "client",
+                        "description": "Clientes e escolhas por campo",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Client"
+                            "$ref": "#/definitions/controllers.mergeClientsRequest"
                         }
                     }
                 ],
@@ -472,11 +731,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Client"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -492,30 +759,74 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Documento ou e-mail já usado por outro cliente: {error, field, client_id}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove um cliente pelo ID",
+            }
+        },
+        "/clients/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Busca por trecho do nome (sem diferenciar acentos e tolerando erros de digitação), do email e dos dígitos do telefone ou do CPF/CNPJ.\nOs resultados vêm do mais para o menos relevante, com a relevância em rank.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "clients"
                 ],
-                "summary": "Deleta um cliente",
+                "summary": "Busca clientes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do cliente",
-                        "name": "id",
-                        "in": "path",
+                        "example": "acme comercio",
+                        "description": "Texto buscado",
+                        "name": "q",
+                        "in": "query",
                         "required": true
-                    }
-                ],
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de resultados (padrão 20, máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes sem nenhuma dessas tags",
+                        "name": "withoutTag",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repositories.ClientSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -523,8 +834,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -535,33 +846,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/files": {
-            "get": {
+        "/clients/tags": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retorna todos os arquivos processados",
+                "description": "Aplica as tags de add e retira as de remove de todos os clientes de client_ids (até 1000), em uma transação.\nTags de add que ainda não existem são criadas. IDs de clientes inexistentes são ignorados e listados em not_found.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "clients"
+                ],
+                "summary": "Marca e desmarca clientes com tags, em lote",
+                "parameters": [
+                    {
+                        "description": "Clientes e tags",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.clientTagsRequest"
+                        }
+                    }
                 ],
-                "summary": "Lista todos os arquivos",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.FileProcess"
-                            }
+                            "$ref": "#/definitions/repositories.ClientTagsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -572,14 +901,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/sendFiles": {
+        "/clients/upload": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Faz upload de um arquivo e registra no sistema",
+                "description": "Recebe uma planilha (.xlsx, .xls, .ods, .csv ou .tsv), lê os dados e cadastra clientes no banco.\nEm CSV/TSV a codificação (UTF-8, UTF-16 ou Windows-1252) e o separador (; , tab |) são detectados automaticamente.\nCom dryRun=true nada é gravado: a resposta traz o resultado previsto de cada linha e um preview_token\nque pode ser enviado depois (sem o arquivo) para efetivar a mesma importação, em até 30 minutos (IMPORT_PREVIEW_TTL).\nArquivos com mais de 100.000 linhas (IMPORT_PREVIEW_MAX_ROWS) não recebem preview_token; só as 20 pré-visualizações mais\nrecentes (IMPORT_PREVIEW_MAX) são guardadas, em memória: os tokens se perdem quando a API reinicia.\nColunas de contato (Tipo Contato, Nome Contato, Email Contato, Telefone Contato) são opcionais: com elas a planilha pode trazer um contato por linha,\nrepetindo os dados do cliente (ex.: agrupadas pelo CNPJ). As linhas repetidas acrescentam o contato ao cliente da primeira linha (status contact_added).\nContatos importados não são principais: o email e o telefone do cliente continuam vindo das colunas do cliente.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -587,36 +916,114 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "clients"
                 ],
-                "summary": "Envia arquivo para processamento",
+                "summary": "Upload de clientes via arquivo Excel",
                 "parameters": [
                     {
                         "type": "file",
-                        "description": "Arquivo a ser enviado",
-                        "name": "nomeArquivo",
-                        "in": "formData",
-                        "required": true
+                        "description": "Arquivo de clientes (.xlsx, .xls, .ods, .csv ou .tsv). Obrigatório quando previewToken não é informado",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de um template de importação para mapear as colunas",
+                        "name": "templateId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas valida e simula a importação, sem gravar",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token de uma pré-visualização (dryRun) a ser efetivada",
+                        "name": "previewToken",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aba a importar, pelo nome ou pela posição (1 = primeira aba). Padrão: primeira aba",
+                        "name": "sheet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Importa todas as abas; abas sem o cabeçalho esperado são reportadas e ignoradas",
+                        "name": "allSheets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Linha do cabeçalho (padrão 1), para planilhas com títulos acima dele",
+                        "name": "headerRow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limite de linhas do arquivo; pode reduzir, mas não aumentar, o limite configurado em IMPORT_MAX_ROWS (padrão 1.000.000)",
+                        "name": "maxRows",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "true",
+                            "savepoint"
+                        ],
+                        "type": "string",
+                        "description": "true: grava tudo em uma transação e desfaz a importação inteira se alguma linha falhar; savepoint: pula as linhas com problema e confirma o restante de uma vez",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "insert-only",
+                            "upsert",
+                            "replace"
+                        ],
+                        "type": "string",
+                        "description": "insert-only (padrão): ignora clientes existentes; upsert: atualiza os campos preenchidos que mudaram; replace: substitui todos os campos",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Completa os campos vazios das linhas com CNPJ (razão social, email, telefone, endereço) pelo cadastro de empresas. Linhas em que a consulta falha são importadas como estão, com enrich_error",
+                        "name": "enrich",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "document",
+                            "email",
+                            "name+email"
+                        ],
+                        "type": "string",
+                        "description": "Chave para encontrar o cliente existente. Padrão: nome+documento, ou nome+email quando não há documento",
+                        "name": "matchKey",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Resultado do dry-run",
                         "schema": {
-                            "$ref": "#/definitions/models.FileProcess"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "201": {
+                        "description": "Totais da importação e import_job_id, usado para consultar ou desfazer a importação em /imports",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -624,8 +1031,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -636,25 +1043,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/{id}": {
+        "/clients/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retorna um arquivo específico pelo ID",
+                "description": "Retorna um cliente pelo ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "clients"
                 ],
-                "summary": "Busca arquivo por ID",
+                "summary": "Busca cliente por ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do arquivo",
+                        "example": "\"b3e1c2d0-1234-4abc-9def-1234567890ab\"",
+                        "description": "ID do cliente",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -664,7 +1072,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.FileProcess"
+                            "$ref": "#/definitions/models.Client"
                         }
                     },
                     "400": {
@@ -677,7 +1085,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -702,7 +1110,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Atualiza os dados de um arquivo existente",
+                "description": "Substitui todos os dados do cliente pelos do corpo: campos ausentes ou vazios ficam vazios.\nPara alterar só alguns campos use PATCH /clients/{id}. Retorna o cliente como foi gravado.",
                 "consumes": [
                     "application/json"
                 ],
@@ -710,24 +1118,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "clients"
                 ],
-                "summary": "Atualiza um arquivo",
+                "summary": "Substitui um cliente",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do arquivo",
+                        "description": "ID do cliente",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados atualizados",
-                        "name": "file",
+                        "description": "Dados do cliente",
+                        "name": "client",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FileProcess"
+                            "$ref": "#/definitions/models.Client"
                         }
                     }
                 ],
@@ -735,20 +1143,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.FileProcess"
+                            "$ref": "#/definitions/models.Client"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -764,6 +1170,15 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Documento ou e-mail já usado por outro cliente: {error, field, client_id}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
@@ -773,38 +1188,104 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove um arquivo pelo ID",
+                "description": "Remove um cliente pelo ID",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "files"
+                    "clients"
                 ],
-                "summary": "Remove um arquivo",
+                "summary": "Deleta um cliente",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do arquivo",
+                        "description": "ID do cliente",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Aplica um JSON Merge Patch (RFC 7396): só os campos enviados mudam, null limpa o campo e address_parts é mesclado campo a campo.\nEx.: {\"email\": null, \"address_parts\": {\"number\": \"120\"}}. Alterar document recalcula person_type e cnpj, a menos que venham no patch;\nalterar address (texto) recalcula address_parts e vice-versa. Retorna o cliente como foi gravado.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Altera campos de um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a alterar",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Client"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -820,39 +1301,60 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Documento ou e-mail já usado por outro cliente: {error, field, client_id}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/files/{id}/download": {
+        "/clients/{id}/contacts": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Realiza o download do arquivo original enviado para o S3",
+                "description": "Retorna os contatos (financeiro, comercial, técnico...) do cliente, o principal primeiro",
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "clients"
                 ],
-                "summary": "Download do arquivo",
+                "summary": "Lista os contatos do cliente",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do arquivo",
+                        "description": "ID do cliente",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "302": {
-                        "description": "Redirect para o arquivo no S3",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ClientContact"
+                            }
                         }
                     },
                     "400": {
@@ -865,7 +1367,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "API key inválida ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -883,10 +1385,2218 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "O contato precisa de email ou telefone. Tipos: billing, commercial, technical ou other (também aceitos em português: financeiro, comercial, técnico, outro).\nCom primary=true o contato passa a ser o principal: os demais deixam de ser e o email e o telefone do cliente passam a ser os dele.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Cria um contato do cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados do contato",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientContact"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientContact"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "E-mail do contato principal já usado por outro cliente: {error, field, client_id}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/{id}/contacts/{contactId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Busca um contato do cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do contato",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientContact"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Substitui os dados do contato. Com primary=true ele passa a ser o principal e o email e o telefone do cliente são atualizados.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Atualiza um contato do cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do contato",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados do contato",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientContact"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientContact"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "E-mail do contato principal já usado por outro cliente: {error, field, client_id}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "O email e o telefone do cliente são mantidos, mesmo que o contato fosse o principal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Exclui um contato do cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do contato",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/{id}/enrich": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Consulta o CNPJ do cliente no cadastro de empresas (BrasilAPI ou outra API configurada em COMPANY_REGISTRY_URL) e preenche\nrazão social (name), email, telefone e endereço. Sem overwrite só campos vazios são preenchidos, e o endereço só se o cliente não tiver um.\nDados do cadastro que não passam na validação são ignorados. As consultas ficam em cache (COMPANY_REGISTRY_CACHE_TTL) e\nrespeitam o limite de COMPANY_REGISTRY_RATE consultas por minuto.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Completa o cliente com os dados do CNPJ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Substitui também os campos já preenchidos",
+                        "name": "overwrite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{client, enriched_fields, company}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Cliente sem CNPJ",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente ou CNPJ não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "E-mail do cadastro já usado por outro cliente: {error, field, client_id}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lista as versões do cliente, da mais recente para a mais antiga: ação (created, updated, deleted, merged, restored),\ncampos alterados com valor antigo e novo, autor (usuário da API key), ID da requisição (X-Request-ID) e data.\nClientes excluídos continuam com histórico.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Histórico de alterações do cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ClientRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/{id}/history/{version}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Volta os dados do cliente aos da versão informada e registra uma nova versão \"restored\"; o histórico não é apagado.\nUm cliente excluído volta a existir. Versões de exclusão não podem ser restauradas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Restaura o cliente a uma versão do histórico",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Versão a restaurar",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Client"
+                        }
+                    },
+                    "400": {
+                        "description": "Versão de exclusão ou com documento inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Documento ou e-mail da versão já usado por outro cliente: {error, field, client_id}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/exports": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Aceita os mesmos parâmetros de GET /clients/export (formato, colunas, opções do CSV e filtros) e processa a exportação em segundo plano.\nAcompanhe o andamento e obtenha o link de download em GET /exports/{id}. O arquivo fica disponível por EXPORT_TTL (padrão 24h).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Agenda uma exportação de clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "xlsx, csv ou jsonl",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Colunas separadas por vírgula, na ordem desejada",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Separador do CSV: ; , | ou tab (padrão ;)",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Grava o BOM UTF-8 no CSV (padrão true)",
+                        "name": "bom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Telefone em qualquer formato",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cidade",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UF (sigla do estado)",
+                        "name": "uf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CEP",
+                        "name": "cep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes criados ou alterados por último nessa importação",
+                        "name": "importJobId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes com todas essas tags (separadas por vírgula ou com o parâmetro repetido)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente clientes sem nenhuma dessas tags",
+                        "name": "withoutTag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna a situação (pending, running, done, failed, expired), o progresso de 0 a 1 e, quando concluída, um link temporário para download",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Consulta uma exportação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da exportação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/files": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna uma página de arquivos processados no envelope {data, total, limit, offset, next_cursor, links}.\nFiltros por campo (id, fileName, status, received_at) com operadores: ?status=x, ?fileName[ilike]=trecho, ?status[in]=a,b, ?received_at[gte]=2024-01-01, ?received_at[lte]=...",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Lista os arquivos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "-received_at",
+                        "description": "Campos separados por vírgula; prefixo - para ordem decrescente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens a pular",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor da próxima página (next_cursor da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/files/sendFiles": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Faz upload de um arquivo e registra no sistema",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Envia arquivo para processamento",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Arquivo a ser enviado",
+                        "name": "nomeArquivo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileProcess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna um arquivo específico pelo ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Busca arquivo por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do arquivo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileProcess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Atualiza os dados de um arquivo existente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Atualiza um arquivo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do arquivo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados atualizados",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FileProcess"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileProcess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove um arquivo pelo ID",
+                "tags": [
+                    "files"
+                ],
+                "summary": "Remove um arquivo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do arquivo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Realiza o download do arquivo original enviado para o S3",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Download do arquivo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do arquivo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect para o arquivo no S3",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/import-templates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna todos os templates de mapeamento de colunas para importação de clientes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import-templates"
+                ],
+                "summary": "Lista os templates de importação",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ImportTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cria um mapeamento de colunas da planilha de origem para campos do cliente (name, email, phone, address, document, person_type)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import-templates"
+                ],
+                "summary": "Cria um template de importação",
+                "parameters": [
+                    {
+                        "description": "Template de importação",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/import-templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import-templates"
+                ],
+                "summary": "Busca template de importação por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do template",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import-templates"
+                ],
+                "summary": "Atualiza um template de importação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do template",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template de importação",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import-templates"
+                ],
+                "summary": "Remove um template de importação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do template",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/imports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna o histórico de importações (arquivo, quem importou, quando, opções e totais), da mais recente para a mais antiga",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Lista as importações de clientes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ImportJob"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna a importação e os clientes inseridos e atualizados por ela",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Busca importação por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da importação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Exclui os clientes inseridos pela importação e restaura os dados anteriores dos clientes atualizados por ela.\nClientes alterados depois do job (manualmente ou por outra importação) são mantidos e listados em skipped.\nSó importações concluídas (completed) ou interrompidas pelo limite de linhas (interrupted) podem ser desfeitas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Desfaz uma importação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da importação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Importação não pode ser desfeita, ou um cliente restaurado teria o documento ou o e-mail de outro",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/segments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Lista os segmentos de clientes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Segment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Um segmento guarda um filtro, não uma lista de clientes: os membros são calculados a cada consulta em GET /segments/{id}/clients.\nO filtro usa a sintaxe da query string de GET /clients, ex.: \"tag=VIP\u0026withoutTag=inadimplente\u0026person_type=PJ\u0026address_parts.uf[in]=RS,SC,PR\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Cria um segmento de clientes",
+                "parameters": [
+                    {
+                        "description": "Segmento",
+                        "name": "segment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Já existe um segmento com esse nome",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/segments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Busca segmento por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Atualiza um segmento de clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Segmento",
+                        "name": "segment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Já existe um segmento com esse nome",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Os clientes não são alterados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Remove um segmento de clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/segments/{id}/clients": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Avalia o filtro do segmento agora e retorna uma página dos clientes no envelope {data, total, limit, offset, next_cursor, links}, como GET /clients.\nOrdenação e paginação vêm da query string; filtros extras na query string restringem ainda mais o segmento.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Lista os clientes de um segmento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos separados por vírgula; prefixo - para ordem decrescente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens a pular",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor da próxima página (next_cursor da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna as tags em ordem alfabética, com a quantidade de clientes de cada uma",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Lista as tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repositories.TagWithCount"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cria uma tag sem clientes. Tags também são criadas automaticamente ao marcar clientes em POST /clients/tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Cria uma tag",
+                "parameters": [
+                    {
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Já existe uma tag com esse nome",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Os clientes marcados continuam com a tag, agora com o novo nome",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Renomeia uma tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da tag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou lista de erros de validação {field, code, message}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Já existe uma tag com esse nome",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "A tag é retirada de todos os clientes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Remove uma tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da tag",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API key inválida ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "controllers.clientTagsRequest": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "description": "nomes das tags a aplicar; as inexistentes são criadas",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remove": {
+                    "description": "nomes das tags a retirar",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controllers.mergeClientsRequest": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "campo -\u003e \"keep\" ou \"remove\"",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "keep_id": {
+                    "type": "string",
+                    "example": "b3e1c2d0-1234-4abc-9def-1234567890ab"
+                },
+                "remove_id": {
+                    "type": "string",
+                    "example": "c4f2d3e1-2345-4bcd-8ef0-234567890abc"
+                }
+            }
+        },
+        "models.Address": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "description": "somente dígitos",
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "complement": {
+                    "type": "string"
+                },
+                "neighborhood": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Book": {
             "type": "object",
             "properties": {
@@ -908,9 +3618,65 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "address": {
+                    "description": "endereço em uma linha, mantido por compatibilidade",
                     "type": "string"
                 },
+                "address_parts": {
+                    "$ref": "#/definitions/models.Address"
+                },
                 "cnpj": {
+                    "description": "legado: espelha Document quando o cliente é PJ",
+                    "type": "string"
+                },
+                "document": {
+                    "description": "CPF ou CNPJ, somente dígitos",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "import_job_id": {
+                    "description": "última importação que criou ou alterou o cliente",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "person_type": {
+                    "description": "PF ou PJ",
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "como informado pelo usuário",
+                    "type": "string"
+                },
+                "phone_e164": {
+                    "description": "normalizado, ex.: +5511987654321",
+                    "type": "string"
+                },
+                "phone_type": {
+                    "description": "mobile ou landline",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "carregadas só nas consultas; alteradas em POST /clients/tags",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                }
+            }
+        },
+        "models.ClientContact": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
                 "email": {
@@ -919,10 +3685,149 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "import_job_id": {
+                    "description": "importação que criou o contato",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
                 "phone": {
+                    "description": "como informado pelo usuário",
+                    "type": "string"
+                },
+                "phone_e164": {
+                    "description": "normalizado, ex.: +5511987654321",
+                    "type": "string"
+                },
+                "primary": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "billing, commercial, technical ou other",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClientDuplicate": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "$ref": "#/definitions/models.Client"
+                },
+                "b": {
+                    "$ref": "#/definitions/models.Client"
+                },
+                "reasons": {
+                    "description": "ex.: \"name:0.92\", \"email\", \"phone\", \"document\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "description": "de 0 a 1",
+                    "type": "number"
+                }
+            }
+        },
+        "models.ClientRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "import_job_id": {
+                    "description": "importação que fez a alteração, se houver",
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.Client"
+                },
+                "version": {
+                    "description": "1, 2, 3... por cliente",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExportJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "quando o arquivo será removido do S3",
+                    "type": "string"
+                },
+                "file_key": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "processed": {
+                    "description": "clientes já gravados no arquivo",
+                    "type": "integer"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "clientes a exportar, conhecido quando o processamento começa",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "string"
+                },
+                "old": {
                     "type": "string"
                 }
             }
@@ -950,6 +3855,203 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.ImportJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "options": {
+                    "description": "parâmetros usados: mode, matchKey, atomic, templateId, sheet...",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rolled_back_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "totals": {
+                    "description": "linhas por situação (inserted, updated, invalid...)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.ImportTemplate": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "coluna de origem -\u003e campo do cliente (name, email, phone, address, document, person_type)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Segment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "filter": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "repositories.ClientSearchResult": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "endereço em uma linha, mantido por compatibilidade",
+                    "type": "string"
+                },
+                "address_parts": {
+                    "$ref": "#/definitions/models.Address"
+                },
+                "cnpj": {
+                    "description": "legado: espelha Document quando o cliente é PJ",
+                    "type": "string"
+                },
+                "document": {
+                    "description": "CPF ou CNPJ, somente dígitos",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "import_job_id": {
+                    "description": "última importação que criou ou alterou o cliente",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "person_type": {
+                    "description": "PF ou PJ",
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "como informado pelo usuário",
+                    "type": "string"
+                },
+                "phone_e164": {
+                    "description": "normalizado, ex.: +5511987654321",
+                    "type": "string"
+                },
+                "phone_type": {
+                    "description": "mobile ou landline",
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "tags": {
+                    "description": "carregadas só nas consultas; alteradas em POST /clients/tags",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                }
+            }
+        },
+        "repositories.ClientTagsResult": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "description": "associações cliente-tag criadas",
+                    "type": "integer"
+                },
+                "not_found": {
+                    "description": "IDs de clientes inexistentes, ignorados",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed": {
+                    "description": "associações cliente-tag removidas",
+                    "type": "integer"
+                }
+            }
+        },
+        "repositories.TagWithCount": {
+            "type": "object",
+            "properties": {
+                "clients": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
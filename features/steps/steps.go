@@ -112,12 +112,14 @@ func InitializeScenario(ctx *ScenarioContext) {
 	ctx.Step(`^faço uma requisição (POST|PUT) para "([^"]*)" com o corpo:$`, f.iMakeARequestToWithBody)
 	ctx.Step(`^a resposta deve ter status (\d+)$`, f.theResponseCodeShouldBe)
 	ctx.Step(`^a resposta deve conter uma lista de livros$`, func() error {
-		var arr []interface{}
-		err := json.Unmarshal(f.response.Body.Bytes(), &arr)
-		if err != nil {
-			return fmt.Errorf("esperado um array JSON, erro: %w", err)
+		var page struct {
+			Data []interface{} `json:"data"`
 		}
-		if len(arr) == 0 {
+		err := json.Unmarshal(f.response.Body.Bytes(), &page)
+		if err != nil || page.Data == nil {
+			return fmt.Errorf("esperado o envelope {data: [...]}, erro: %v", err)
+		}
+		if len(page.Data) == 0 {
 			return fmt.Errorf("lista de livros está vazia")
 		}
 		return nil
@@ -136,12 +138,14 @@ func InitializeScenario(ctx *ScenarioContext) {
 		return f.theResponseShouldContain("title", title)
 	})
 	ctx.Step(`^a resposta deve conter uma lista de arquivos$`, func() error {
-		var arr []interface{}
-		err := json.Unmarshal(f.response.Body.Bytes(), &arr)
-		if err != nil {
-			return fmt.Errorf("esperado um array JSON, erro: %w", err)
+		var page struct {
+			Data []interface{} `json:"data"`
+		}
+		err := json.Unmarshal(f.response.Body.Bytes(), &page)
+		if err != nil || page.Data == nil {
+			return fmt.Errorf("esperado o envelope {data: [...]}, erro: %v", err)
 		}
-		if len(arr) == 0 {
+		if len(page.Data) == 0 {
 			return fmt.Errorf("lista de arquivos está vazia")
 		}
 		return nil
@@ -1,20 +1,50 @@
 package middlewares
 
 import (
+	"fmt"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 const ApiKeyEsperada = "minha-chave-secreta" // Troque por sua chave fixa
 
+// ApiKeyUser é o autor registrado para a chave fixa ApiKeyEsperada
+const ApiKeyUser = "api-key"
+
+// ApiKeyMiddleware exige o header X-API-Key. As chaves aceitas vêm de API_KEYS, no formato
+// "usuario:chave,usuario2:chave2"; sem API_KEYS vale só a chave fixa ApiKeyEsperada. O usuário dono da chave
+// é o autor da requisição, lido depois com Actor.
 func ApiKeyMiddleware() gin.HandlerFunc {
+	keys := apiKeys()
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey != ApiKeyEsperada {
+		user, ok := keys[c.GetHeader("X-API-Key")]
+		if !ok {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key inválida ou ausente"})
 			return
 		}
+		c.Set(actorKey, user)
 		c.Next()
 	}
 }
+
+// apiKeys lê as chaves de API_KEYS, indexadas pela chave
+func apiKeys() map[string]string {
+	env := os.Getenv("API_KEYS")
+	if env == "" {
+		return map[string]string{ApiKeyEsperada: ApiKeyUser}
+	}
+	keys := map[string]string{}
+	for _, entry := range strings.Split(env, ",") {
+		user, key, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		user, key = strings.TrimSpace(user), strings.TrimSpace(key)
+		if !ok || user == "" || key == "" {
+			fmt.Println("[AVISO] Entrada inválida em API_KEYS ignorada: use usuario:chave")
+			continue
+		}
+		keys[key] = user
+	}
+	return keys
+}
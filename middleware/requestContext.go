@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderRequestID identifica a requisição nos logs e no histórico de alterações
+	HeaderRequestID = "X-Request-ID"
+
+	requestIDKey = "request_id"
+	actorKey     = "actor"
+)
+
+// RequestContextMiddleware define o ID da requisição (X-Request-ID recebido ou um UUID novo, devolvido no
+// header da resposta), lido depois com RequestID.
+func RequestContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := strings.TrimSpace(c.GetHeader(HeaderRequestID))
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.New().String()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(HeaderRequestID, requestID)
+		c.Next()
+	}
+}
+
+// RequestID retorna o ID da requisição definido por RequestContextMiddleware
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// Actor retorna quem fez a requisição: o usuário da API key autenticada por ApiKeyMiddleware ou, em rotas sem
+// autenticação, o IP de origem. Headers enviados pelo cliente não são usados, pois poderiam ser forjados.
+func Actor(c *gin.Context) string {
+	if actor := c.GetString(actorKey); actor != "" {
+		return actor
+	}
+	return c.ClientIP()
+}
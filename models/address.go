@@ -0,0 +1,132 @@
+package models
+
+import (
+	"minha-api/utils"
+	"regexp"
+	"strings"
+)
+
+// Address é o endereço estruturado do cliente
+type Address struct {
+	Street       string `json:"street"`
+	Number       string `json:"number"`
+	Complement   string `json:"complement"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `gorm:"index" json:"city"`
+	UF           string `gorm:"size:2;index" json:"uf"`
+	CEP          string `gorm:"size:8" json:"cep"` // somente dígitos
+}
+
+var (
+	addressCEPRegex    = regexp.MustCompile(`(?i)(cep[:\s]*)?\b(\d{5})-?(\d{3})\b`)
+	addressUFRegex     = regexp.MustCompile(`(?:^|[\s,/-])([A-Za-z]{2})\s*$`)
+	addressSepRegex    = regexp.MustCompile(`\s*(?:,|;|\s-\s|/)\s*`)
+	addressNumberRegex = regexp.MustCompile(`(?i)^(?:n[º°o.]?\s*)?(\d+[a-z]?|s/?n)$`)
+	semNumeroRegex     = regexp.MustCompile(`(?i)\bs/n\b`)
+	streetNumberRegex  = regexp.MustCompile(`(?i)^(.*\D)\s+(?:n[º°o.]?\s*)?(\d+[a-z]?)$`)
+)
+
+// complementPrefixes identificam trechos que são complemento (apartamento, sala, bloco...)
+var complementPrefixes = []string{"apto", "apt", "ap", "apartamento", "sala", "sl", "bloco", "bl", "casa", "conj", "cj", "lote", "lt", "andar", "loja", "fundos", "quadra", "qd"}
+
+// ParseAddress separa um endereço em texto livre (como a coluna "Endereço" das planilhas antigas)
+// em logradouro, número, complemento, bairro, cidade, UF e CEP.
+// Ex.: "Rua das Flores, 123, Apto 45 - Centro, São Paulo - SP, 01234-567".
+// Trechos que não puderem ser identificados ficam vazios.
+func ParseAddress(s string) Address {
+	var a Address
+	s = strings.TrimSpace(s)
+	if m := addressCEPRegex.FindStringSubmatchIndex(s); m != nil {
+		a.CEP = s[m[4]:m[5]] + s[m[6]:m[7]]
+		s = s[:m[0]] + s[m[1]:]
+	}
+	s = strings.TrimRight(s, " ,;-/")
+	if m := addressUFRegex.FindStringSubmatchIndex(s); m != nil && utils.IsValidUF(s[m[2]:m[3]]) {
+		a.UF = strings.ToUpper(s[m[2]:m[3]])
+		s = strings.TrimRight(s[:m[2]], " ,;-/")
+	}
+
+	// "s/n" (sem número) não pode ser confundido com o separador "/"
+	s = semNumeroRegex.ReplaceAllString(s, "s\x00n")
+	var parts []string
+	for _, p := range addressSepRegex.Split(s, -1) {
+		if p = strings.TrimSpace(strings.ReplaceAll(p, "\x00", "/")); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return a
+	}
+
+	a.Street, parts = parts[0], parts[1:]
+	if len(parts) > 0 && addressNumberRegex.MatchString(parts[0]) {
+		a.Number, parts = addressNumberRegex.FindStringSubmatch(parts[0])[1], parts[1:]
+	} else if m := streetNumberRegex.FindStringSubmatch(a.Street); m != nil {
+		a.Street, a.Number = strings.TrimSpace(m[1]), m[2]
+	}
+	if len(parts) > 0 && isComplement(parts[0]) {
+		a.Complement, parts = parts[0], parts[1:]
+	}
+	switch len(parts) {
+	case 0:
+	case 1:
+		if a.UF != "" {
+			a.City = parts[0]
+		} else {
+			a.Neighborhood = parts[0]
+		}
+	default:
+		a.City = parts[len(parts)-1]
+		a.Neighborhood = strings.Join(parts[:len(parts)-1], " - ")
+	}
+	return a
+}
+
+// String monta o endereço em uma linha, no mesmo formato aceito por ParseAddress
+func (a Address) String() string {
+	line := joinNonEmpty(", ", a.Street, a.Number, a.Complement)
+	line = joinNonEmpty(" - ", line, a.Neighborhood)
+	line = joinNonEmpty(", ", line, joinNonEmpty(" - ", a.City, a.UF))
+	if a.CEP != "" {
+		line = joinNonEmpty(", ", line, utils.FormatCEP(a.CEP))
+	}
+	return line
+}
+
+// IsEmpty indica se nenhuma parte do endereço foi preenchida
+func (a Address) IsEmpty() bool {
+	return a == Address{}
+}
+
+// Normalize padroniza UF em maiúsculas e CEP somente com dígitos
+func (a *Address) Normalize() {
+	a.Street = strings.TrimSpace(a.Street)
+	a.Number = strings.TrimSpace(a.Number)
+	a.Complement = strings.TrimSpace(a.Complement)
+	a.Neighborhood = strings.TrimSpace(a.Neighborhood)
+	a.City = strings.TrimSpace(a.City)
+	a.UF = strings.ToUpper(strings.TrimSpace(a.UF))
+	if utils.IsValidCEP(a.CEP) {
+		a.CEP = utils.OnlyDigits(a.CEP)
+	}
+}
+
+func isComplement(s string) bool {
+	lower := strings.ToLower(utils.RemoveAccents(s))
+	for _, p := range complementPrefixes {
+		if lower == p || strings.HasPrefix(lower, p+" ") || strings.HasPrefix(lower, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"minha-api/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,3 +14,13 @@ type Book struct {
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+// Validate aplica as regras de validação do livro
+func (b *Book) Validate() utils.ValidationErrors {
+	v := &utils.Validator{}
+	v.Required("title", b.Title)
+	v.MaxLength("title", b.Title, 255)
+	v.Required("author", b.Author)
+	v.MaxLength("author", b.Author, 255)
+	return v.Errors()
+}
@@ -1,13 +1,111 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"encoding/json"
+	"minha-api/utils"
+	"strings"
+
+	"gorm.io/gorm"
+)
 
 type Client struct {
-	ID        string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
-	Name      string         `json:"name"`
-	Email     string         `json:"email"`
-	Phone     string         `json:"phone"`
-	Address   string         `json:"address"`
-	CNPJ      string         `json:"cnpj"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name         string         `json:"name"`
+	Email        string         `json:"email"`
+	Phone        string         `json:"phone"`                   // como informado pelo usuário
+	PhoneE164    string         `gorm:"index" json:"phone_e164"` // normalizado, ex.: +5511987654321
+	PhoneType    string         `json:"phone_type"`              // mobile ou landline
+	Address      string         `json:"address"`                 // endereço em uma linha, mantido por compatibilidade
+	AddressParts Address        `gorm:"embedded;embeddedPrefix:address_" json:"address_parts"`
+	CNPJ         string         `json:"cnpj"`                                        // legado: espelha Document quando o cliente é PJ
+	Document     string         `gorm:"index" json:"document"`                       // CPF ou CNPJ, somente dígitos
+	PersonType   string         `gorm:"size:2" json:"person_type"`                   // PF ou PJ
+	ImportJobID  string         `gorm:"index" json:"import_job_id,omitempty"`        // última importação que criou ou alterou o cliente
+	Tags         []Tag          `gorm:"many2many:client_tags" json:"tags,omitempty"` // carregadas só nas consultas; alteradas em POST /clients/tags
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// MarshalJSON acrescenta o documento formatado com máscara à resposta
+func (c Client) MarshalJSON() ([]byte, error) {
+	type client Client
+	return json.Marshal(struct {
+		client
+		DocumentFormatted string `json:"document_formatted,omitempty"`
+	}{client(c), utils.FormatDocument(c.Document)})
+}
+
+// NormalizeDocument valida o CPF/CNPJ do cliente e o guarda somente com dígitos, preenchendo o tipo de pessoa.
+// O documento pode vir em Document ou, por compatibilidade, em CNPJ.
+func (c *Client) NormalizeDocument() error {
+	c.PersonType = strings.ToUpper(strings.TrimSpace(c.PersonType))
+	if c.PersonType != "" && c.PersonType != utils.PersonTypeIndividual && c.PersonType != utils.PersonTypeCompany {
+		return utils.ErrPersonType
+	}
+	raw := strings.TrimSpace(c.Document)
+	if raw == "" {
+		raw = strings.TrimSpace(c.CNPJ)
+	}
+	if raw == "" {
+		c.Document, c.CNPJ = "", ""
+		return nil
+	}
+	doc, err := utils.ParseDocument(raw)
+	if err != nil {
+		return err
+	}
+	if c.PersonType != "" && c.PersonType != doc.PersonType() {
+		return utils.ErrPersonTypeMismatch
+	}
+	c.Document = doc.Digits
+	c.PersonType = doc.PersonType()
+	c.CNPJ = ""
+	if doc.Type == utils.DocumentTypeCNPJ {
+		c.CNPJ = doc.Digits
+	}
+	return nil
+}
+
+// NormalizePhone preenche o telefone em E.164 e o tipo a partir do valor informado.
+// Telefones que não puderem ser interpretados ficam sem forma normalizada.
+func (c *Client) NormalizePhone() {
+	c.PhoneE164, c.PhoneType = "", ""
+	if p, err := utils.ParsePhoneBR(c.Phone); err == nil {
+		c.PhoneE164, c.PhoneType = p.E164(), p.Type
+	}
+}
+
+// NormalizeAddress mantém coerentes o endereço estruturado e o texto em uma linha:
+// se as partes vierem preenchidas o texto é remontado a partir delas, senão as partes são extraídas do texto.
+func (c *Client) NormalizeAddress() {
+	c.AddressParts.Normalize()
+	if !c.AddressParts.IsEmpty() {
+		c.Address = c.AddressParts.String()
+	} else if strings.TrimSpace(c.Address) != "" {
+		c.AddressParts = ParseAddress(c.Address)
+	}
+}
+
+// Validate normaliza o documento e aplica as regras de validação do cliente.
+// É usado tanto no CRUD quanto na importação de planilhas.
+func (c *Client) Validate() utils.ValidationErrors {
+	v := &utils.Validator{}
+	v.Required("name", c.Name)
+	v.MaxLength("name", c.Name, 255)
+	v.Email("email", c.Email)
+	v.MaxLength("email", c.Email, 254)
+	v.Phone("phone", c.Phone)
+	v.MaxLength("phone", c.Phone, 30)
+	c.NormalizePhone()
+	c.NormalizeAddress()
+	v.MaxLength("address", c.Address, 500)
+	v.CEP("address_parts.cep", c.AddressParts.CEP)
+	v.UF("address_parts.uf", c.AddressParts.UF)
+	switch err := c.NormalizeDocument(); err {
+	case nil:
+	case utils.ErrPersonType, utils.ErrPersonTypeMismatch:
+		v.Add("person_type", utils.CodeInvalidValue, err.Error())
+	default:
+		v.Add("document", utils.CodeInvalidDocument, "CPF/CNPJ inválido: "+err.Error())
+	}
+	return v.Errors()
 }
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ClientAlias guarda o ID de um cliente removido em uma mesclagem, apontando para o cliente que ficou.
+// Buscas pelo ID antigo continuam encontrando o cliente mesclado.
+type ClientAlias struct {
+	AliasID   string    `gorm:"primaryKey;type:uuid" json:"alias_id"`
+	ClientID  string    `gorm:"type:uuid;index" json:"client_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
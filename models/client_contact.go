@@ -0,0 +1,120 @@
+package models
+
+import (
+	"minha-api/utils"
+	"strings"
+	"time"
+)
+
+// Tipos de contato de um cliente
+const (
+	ContactBilling    = "billing"    // financeiro / cobrança
+	ContactCommercial = "commercial" // comercial
+	ContactTechnical  = "technical"  // técnico / suporte
+	ContactOther      = "other"
+)
+
+// contactTypeAliases aceita os tipos também em português, como costumam vir nas planilhas
+var contactTypeAliases = map[string]string{
+	"billing":     ContactBilling,
+	"financeiro":  ContactBilling,
+	"cobranca":    ContactBilling,
+	"faturamento": ContactBilling,
+	"commercial":  ContactCommercial,
+	"comercial":   ContactCommercial,
+	"vendas":      ContactCommercial,
+	"technical":   ContactTechnical,
+	"tecnico":     ContactTechnical,
+	"suporte":     ContactTechnical,
+	"other":       ContactOther,
+	"outro":       ContactOther,
+	"outros":      ContactOther,
+}
+
+// ClientContact é uma pessoa ou canal de contato de um cliente (financeiro, comercial, técnico...).
+// O contato principal (Primary) é espelhado em Email e Phone do cliente.
+type ClientContact struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	ClientID    string    `gorm:"type:uuid;index" json:"client_id"`
+	Type        string    `gorm:"size:20" json:"type"` // billing, commercial, technical ou other
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`      // como informado pelo usuário
+	PhoneE164   string    `json:"phone_e164"` // normalizado, ex.: +5511987654321
+	Primary     bool      `json:"primary"`
+	ImportJobID string    `gorm:"index" json:"import_job_id,omitempty"` // importação que criou o contato
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NormalizeContactType converte o tipo informado (em inglês ou português, com ou sem acento) para um
+// dos tipos de contato. Vazio vira other; tipos desconhecidos retornam ok=false.
+func NormalizeContactType(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(utils.RemoveAccents(s)))
+	if s == "" {
+		return ContactOther, true
+	}
+	t, ok := contactTypeAliases[s]
+	return t, ok
+}
+
+// Validate normaliza tipo e telefone e aplica as regras do contato: precisa de email ou telefone
+func (c *ClientContact) Validate() utils.ValidationErrors {
+	v := &utils.Validator{}
+	if t, ok := NormalizeContactType(c.Type); ok {
+		c.Type = t
+	} else {
+		v.Add("type", utils.CodeInvalidValue, "Tipo de contato inválido. Use billing, commercial, technical ou other")
+	}
+	v.MaxLength("name", c.Name, 255)
+	if strings.TrimSpace(c.Email) == "" && strings.TrimSpace(c.Phone) == "" {
+		v.Add("email", utils.CodeRequired, "Informe email ou telefone do contato")
+	}
+	v.Email("email", c.Email)
+	v.MaxLength("email", c.Email, 254)
+	v.Phone("phone", c.Phone)
+	v.MaxLength("phone", c.Phone, 30)
+	c.PhoneE164 = ""
+	if p, err := utils.ParsePhoneBR(c.Phone); err == nil {
+		c.PhoneE164 = p.E164()
+	}
+	return v.Errors()
+}
+
+// SameAs indica se os dois contatos representam a mesma pessoa/canal: mesmo tipo, nome, email e telefone,
+// sem diferenciar maiúsculas nem a formatação do telefone. Evita contatos repetidos ao reimportar uma planilha.
+func (c *ClientContact) SameAs(o *ClientContact) bool {
+	phone := func(ct *ClientContact) string {
+		if ct.PhoneE164 != "" {
+			return ct.PhoneE164
+		}
+		return strings.TrimSpace(ct.Phone)
+	}
+	return c.Type == o.Type &&
+		strings.EqualFold(strings.TrimSpace(c.Name), strings.TrimSpace(o.Name)) &&
+		strings.EqualFold(strings.TrimSpace(c.Email), strings.TrimSpace(o.Email)) &&
+		phone(c) == phone(o)
+}
+
+// ApplyTo copia email e telefone do contato para o cliente, recalculando o telefone normalizado.
+// Usado quando o contato passa a ser o principal. Campos vazios no contato mantêm o valor do cliente:
+// um contato só com telefone não apaga o email do cliente.
+func (c *ClientContact) ApplyTo(client *Client) {
+	if strings.TrimSpace(c.Email) != "" {
+		client.Email = c.Email
+	}
+	if strings.TrimSpace(c.Phone) != "" {
+		client.Phone = c.Phone
+		client.NormalizePhone()
+	}
+}
+
+// SyncFrom faz o contato principal espelhar o email e o telefone do cliente (já normalizado) e indica se
+// algo mudou. Usado quando o cliente é criado ou alterado diretamente.
+func (c *ClientContact) SyncFrom(client *Client) bool {
+	if c.Email == client.Email && c.Phone == client.Phone && c.PhoneE164 == client.PhoneE164 {
+		return false
+	}
+	c.Email, c.Phone, c.PhoneE164 = client.Email, client.Phone, client.PhoneE164
+	return true
+}
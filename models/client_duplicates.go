@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"minha-api/utils"
+	"sort"
+	"strings"
+)
+
+// Pesos da nota de duplicidade: o nome responde por metade da nota e os dados de contato pela outra metade
+const (
+	duplicateNameWeight     = 0.5
+	duplicateDocumentWeight = 0.5
+	duplicateEmailWeight    = 0.3
+	duplicatePhoneWeight    = 0.2
+)
+
+// DuplicateNameMaxScore é a maior nota de um par que só tem o nome parecido, sem documento, email ou
+// telefone em comum. Com nota mínima acima dela, só pares com algum desses dados iguais interessam.
+const DuplicateNameMaxScore = duplicateNameWeight
+
+// ClientDuplicate é um par de clientes que provavelmente são a mesma pessoa ou empresa
+type ClientDuplicate struct {
+	A       Client   `json:"a"`
+	B       Client   `json:"b"`
+	Score   float64  `json:"score"`   // de 0 a 1
+	Reasons []string `json:"reasons"` // ex.: "name:0.92", "email", "phone", "document"
+}
+
+// ScoreClientDuplicate dá uma nota de 0 a 1 à chance de dois clientes serem o mesmo: metade vem da
+// semelhança dos nomes normalizados (ver utils.NormalizeName) e metade de documento, email ou telefone iguais.
+func ScoreClientDuplicate(a, b *Client) (float64, []string) {
+	nameScore := utils.NameSimilarity(utils.NormalizeName(a.Name), utils.NormalizeName(b.Name))
+	score := duplicateNameWeight * nameScore
+	reasons := []string{fmt.Sprintf("name:%.2f", nameScore)}
+
+	evidence := 0.0
+	if a.Document != "" && a.Document == b.Document {
+		evidence += duplicateDocumentWeight
+		reasons = append(reasons, "document")
+	}
+	if a.Email != "" && strings.EqualFold(a.Email, b.Email) {
+		evidence += duplicateEmailWeight
+		reasons = append(reasons, "email")
+	}
+	if a.PhoneE164 != "" && a.PhoneE164 == b.PhoneE164 {
+		evidence += duplicatePhoneWeight
+		reasons = append(reasons, "phone")
+	}
+	return score + min(evidence, 1-duplicateNameWeight), reasons
+}
+
+// ScoreDuplicateCandidates dá nota aos pares candidatos (ver repositories.ClientRepository.DuplicateCandidates)
+// e retorna os que atingem minScore, na ordem de SortClientDuplicates
+func ScoreDuplicateCandidates(pairs [][2]Client, minScore float64) []ClientDuplicate {
+	var duplicates []ClientDuplicate
+	for i := range pairs {
+		a, b := &pairs[i][0], &pairs[i][1]
+		if score, reasons := ScoreClientDuplicate(a, b); score >= minScore {
+			duplicates = append(duplicates, ClientDuplicate{A: *a, B: *b, Score: score, Reasons: reasons})
+		}
+	}
+	SortClientDuplicates(duplicates)
+	return duplicates
+}
+
+// SortClientDuplicates ordena os pares da maior para a menor nota; empates pelos IDs, para a ordem ser estável
+func SortClientDuplicates(duplicates []ClientDuplicate) {
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Score != duplicates[j].Score {
+			return duplicates[i].Score > duplicates[j].Score
+		}
+		return duplicates[i].A.ID+duplicates[i].B.ID < duplicates[j].A.ID+duplicates[j].B.ID
+	})
+}
+
+// Origem do valor de um campo na mesclagem de clientes
+const (
+	MergeKeep   = "keep"
+	MergeRemove = "remove"
+)
+
+// MergeClients combina remove em keep e retorna o cliente resultante, com o ID de keep. winners escolhe,
+// por campo (nomes de ClientFields), de qual cliente vem o valor; sem escolha vale o de keep, ou o de remove
+// quando keep não tem o campo preenchido. O endereço é tratado como um bloco: "address" decide também
+// todas as partes (address_parts.*), para não misturar ruas e cidades de endereços diferentes.
+func MergeClients(keep, remove *Client, winners map[string]string) (Client, utils.ValidationErrors) {
+	var v utils.Validator
+	known := map[string]bool{}
+	for _, f := range ClientFields {
+		known[f.Name] = true
+	}
+	for field, winner := range winners {
+		if !known[field] {
+			v.Add("fields."+field, utils.CodeInvalidValue, "Campo desconhecido")
+		} else if isAddressField(field) && field != "address" {
+			v.Add("fields."+field, utils.CodeInvalidValue, "O endereço é escolhido inteiro, pelo campo address")
+		} else if winner != MergeKeep && winner != MergeRemove {
+			v.Add("fields."+field, utils.CodeInvalidValue, "Use keep ou remove")
+		}
+	}
+	if errs := v.Errors(); errs != nil {
+		return Client{}, errs
+	}
+
+	merged := *keep
+	keepAddress := !keep.AddressParts.IsEmpty() || keep.Address != ""
+	for _, f := range ClientFields {
+		winner, ok := winners[f.Name]
+		if isAddressField(f.Name) {
+			if winner, ok = winners["address"]; !ok {
+				winner = MergeKeep
+				if !keepAddress {
+					winner = MergeRemove
+				}
+			}
+		} else if !ok {
+			winner = MergeKeep
+			if f.Get(keep) == "" {
+				winner = MergeRemove
+			}
+		}
+		if winner == MergeRemove {
+			f.Set(&merged, f.Get(remove))
+		}
+	}
+	return merged, nil
+}
+
+func isAddressField(name string) bool {
+	return name == "address" || strings.HasPrefix(name, "address_parts.")
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"minha-api/utils"
+	"strings"
+)
+
+// ApplyCompanyInfo preenche o cliente com os dados do cadastro de empresas e retorna os campos alterados.
+// Sem overwrite só campos vazios são preenchidos. O endereço é tratado como um todo: só é trocado se o
+// cliente não tiver endereço (ou com overwrite), para não misturar partes de dois endereços.
+func (c *Client) ApplyCompanyInfo(info *utils.CompanyInfo, overwrite bool) []string {
+	company := Client{
+		Name:  info.LegalName,
+		Email: info.Email,
+		Phone: info.Phone,
+		AddressParts: Address{
+			Street:       info.Street,
+			Number:       info.Number,
+			Complement:   info.Complement,
+			Neighborhood: info.Neighborhood,
+			City:         info.City,
+			UF:           info.UF,
+			CEP:          info.CEP,
+		},
+	}
+	replaceAddress := !company.AddressParts.IsEmpty() &&
+		(overwrite || (c.AddressParts.IsEmpty() && strings.TrimSpace(c.Address) == ""))
+
+	var changed []string
+	addressChanged := false
+	for _, f := range ClientFields {
+		cur, v := f.Get(c), f.Get(&company)
+		switch {
+		case f.Name == "address":
+			continue // remontado a partir das partes na validação
+		case strings.HasPrefix(f.Name, "address_parts."):
+			if !replaceAddress {
+				continue
+			}
+		case v == "" || (cur != "" && !overwrite):
+			continue
+		}
+		if cur != v {
+			f.Set(c, v)
+			changed = append(changed, f.Name)
+			addressChanged = addressChanged || strings.HasPrefix(f.Name, "address_parts.")
+		}
+	}
+	if addressChanged {
+		c.Address = "" // remontado a partir das partes em NormalizeAddress
+		changed = append(changed, "address")
+	}
+	return changed
+}
@@ -0,0 +1,44 @@
+package models
+
+// ClientField descreve um campo editável do cliente pelo nome usado no JSON.
+// Campos derivados (phone_e164, phone_type, cnpj) não entram na lista: são recalculados na validação.
+type ClientField struct {
+	Name string
+	Get  func(c *Client) string
+	Set  func(c *Client, v string)
+}
+
+// ClientFields lista os campos editáveis do cliente, na ordem em que aparecem na API
+var ClientFields = []ClientField{
+	{"name", func(c *Client) string { return c.Name }, func(c *Client, v string) { c.Name = v }},
+	{"email", func(c *Client) string { return c.Email }, func(c *Client, v string) { c.Email = v }},
+	{"phone", func(c *Client) string { return c.Phone }, func(c *Client, v string) { c.Phone = v }},
+	{"document", func(c *Client) string { return c.Document }, func(c *Client, v string) { c.Document = v }},
+	{"person_type", func(c *Client) string { return c.PersonType }, func(c *Client, v string) { c.PersonType = v }},
+	{"address", func(c *Client) string { return c.Address }, func(c *Client, v string) { c.Address = v }},
+	{"address_parts.street", func(c *Client) string { return c.AddressParts.Street }, func(c *Client, v string) { c.AddressParts.Street = v }},
+	{"address_parts.number", func(c *Client) string { return c.AddressParts.Number }, func(c *Client, v string) { c.AddressParts.Number = v }},
+	{"address_parts.complement", func(c *Client) string { return c.AddressParts.Complement }, func(c *Client, v string) { c.AddressParts.Complement = v }},
+	{"address_parts.neighborhood", func(c *Client) string { return c.AddressParts.Neighborhood }, func(c *Client, v string) { c.AddressParts.Neighborhood = v }},
+	{"address_parts.city", func(c *Client) string { return c.AddressParts.City }, func(c *Client, v string) { c.AddressParts.City = v }},
+	{"address_parts.uf", func(c *Client) string { return c.AddressParts.UF }, func(c *Client, v string) { c.AddressParts.UF = v }},
+	{"address_parts.cep", func(c *Client) string { return c.AddressParts.CEP }, func(c *Client, v string) { c.AddressParts.CEP = v }},
+}
+
+// MergeClientFields copia para dst os campos de src que mudaram e retorna os nomes dos campos alterados.
+// Com overwriteEmpty=false valores vazios em src são ignorados (atualização parcial);
+// com overwriteEmpty=true dst passa a ter exatamente os valores de src.
+func MergeClientFields(dst, src *Client, overwriteEmpty bool) []string {
+	var changed []string
+	for _, f := range ClientFields {
+		v := f.Get(src)
+		if v == "" && !overwriteEmpty {
+			continue
+		}
+		if f.Get(dst) != v {
+			f.Set(dst, v)
+			changed = append(changed, f.Name)
+		}
+	}
+	return changed
+}
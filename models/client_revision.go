@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Ações registradas no histórico do cliente
+const (
+	RevisionCreated  = "created"
+	RevisionUpdated  = "updated"
+	RevisionDeleted  = "deleted"
+	RevisionMerged   = "merged"   // recebeu os dados de outro cliente em uma mesclagem
+	RevisionRestored = "restored" // voltou aos dados de uma revisão anterior
+)
+
+// FieldChange é a alteração de um campo do cliente, pelo nome usado no JSON (ver ClientFields)
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ClientRevision é uma versão do cliente: quem alterou, quando, em qual requisição e o que mudou.
+// Snapshot guarda o cliente como ficou depois da alteração (em exclusões, como estava antes dela),
+// permitindo restaurar qualquer versão.
+type ClientRevision struct {
+	ID          uint          `gorm:"primaryKey" json:"-"`
+	ClientID    string        `gorm:"type:uuid;uniqueIndex:idx_client_revisions_version" json:"client_id"`
+	Version     int           `gorm:"uniqueIndex:idx_client_revisions_version" json:"version"` // 1, 2, 3... por cliente
+	Action      string        `json:"action"`
+	Changes     []FieldChange `gorm:"type:jsonb;serializer:json" json:"changes"`
+	Snapshot    *Client       `gorm:"type:jsonb;serializer:json" json:"snapshot,omitempty"`
+	Actor       string        `json:"actor"`
+	RequestID   string        `gorm:"index" json:"request_id,omitempty"`
+	ImportJobID string        `json:"import_job_id,omitempty"` // importação que fez a alteração, se houver
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// DiffClients compara os campos editáveis de dois estados do cliente. Use um cliente vazio como before
+// em criações e como after em exclusões.
+func DiffClients(before, after *Client) []FieldChange {
+	changes := []FieldChange{}
+	for _, f := range ClientFields {
+		if old, cur := f.Get(before), f.Get(after); old != cur {
+			changes = append(changes, FieldChange{Field: f.Name, Old: old, New: cur})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Situações de um job de exportação
+const (
+	ExportJobPending = "pending"
+	ExportJobRunning = "running"
+	ExportJobDone    = "done"
+	ExportJobFailed  = "failed"
+	ExportJobExpired = "expired" // arquivo já removido do S3
+)
+
+// ExportJob é uma exportação de clientes processada em segundo plano. Query guarda os parâmetros pedidos
+// (os mesmos de GET /clients/export), já com o formato resolvido.
+type ExportJob struct {
+	ID         string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Query      string     `json:"query"`
+	Format     string     `json:"format"`
+	Status     string     `gorm:"index" json:"status"`
+	Total      int        `json:"total"`     // clientes a exportar, conhecido quando o processamento começa
+	Processed  int        `json:"processed"` // clientes já gravados no arquivo
+	FileKey    string     `json:"file_key,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time `gorm:"index" json:"expires_at,omitempty"` // quando o arquivo será removido do S3
+}
+
+// Progress é a fração já processada, de 0 a 1
+func (j *ExportJob) Progress() float64 {
+	if j.Status == ExportJobDone || j.Status == ExportJobExpired {
+		return 1
+	}
+	if j.Total == 0 {
+		return 0
+	}
+	return float64(j.Processed) / float64(j.Total)
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// Situações de um job de importação
+const (
+	ImportJobProcessing  = "processing"
+	ImportJobCompleted   = "completed"
+	ImportJobCancelled   = "cancelled"   // importação atômica desfeita: nada foi gravado
+	ImportJobInterrupted = "interrupted" // limite de linhas atingido: as linhas anteriores ao limite foram gravadas
+	ImportJobRolledBack  = "rolled_back"
+)
+
+// Ações registradas no histórico de um job de importação
+const (
+	ImportActionInserted = "inserted"
+	ImportActionUpdated  = "updated"
+)
+
+// ImportJob registra uma importação de clientes: arquivo de origem, quem e quando importou, opções e totais
+type ImportJob struct {
+	ID           string            `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	FileName     string            `json:"file_name"`
+	CreatedBy    string            `json:"created_by"`
+	Options      map[string]string `gorm:"type:jsonb;serializer:json" json:"options"` // parâmetros usados: mode, matchKey, atomic, templateId, sheet...
+	Totals       map[string]int    `gorm:"type:jsonb;serializer:json" json:"totals"`  // linhas por situação (inserted, updated, invalid...)
+	Status       string            `gorm:"index" json:"status"`
+	CreatedAt    time.Time         `json:"created_at"`
+	FinishedAt   *time.Time        `json:"finished_at,omitempty"`
+	RolledBackAt *time.Time        `json:"rolled_back_at,omitempty"`
+}
+
+// Rollbackable indica se o job gravou clientes que podem ser desfeitos: concluído ou interrompido pelo limite de
+// linhas. Jobs em andamento, cancelados ou já desfeitos não podem.
+func (j *ImportJob) Rollbackable() bool {
+	return j.Status == ImportJobCompleted || j.Status == ImportJobInterrupted
+}
+
+// RollbackSkipReason indica por que o rollback do job deve manter o cliente como está: current é o cliente
+// atual e later a revisão mais recente feita fora do job depois dele (nil se não houver). Retorna "" se o
+// cliente pode ser revertido.
+func (j *ImportJob) RollbackSkipReason(current *Client, later *ClientRevision) string {
+	switch {
+	case current.ImportJobID != j.ID || (later != nil && later.ImportJobID != ""):
+		return "alterado por outra importação"
+	case later != nil:
+		return "alterado depois da importação"
+	}
+	return ""
+}
+
+// ImportJobChange é um cliente inserido ou atualizado por um job de importação.
+// Em atualizações, Previous guarda os dados do cliente antes da importação, para permitir desfazê-la.
+type ImportJobChange struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	ImportJobID string    `gorm:"type:uuid;index" json:"import_job_id"`
+	ClientID    string    `gorm:"type:uuid;index" json:"client_id"`
+	Action      string    `json:"action"`
+	Line        int       `json:"line"`
+	Previous    *Client   `gorm:"type:jsonb;serializer:json" json:"previous,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ImportTemplate mapeia colunas de uma planilha de origem para campos de Client
+type ImportTemplate struct {
+	ID          string            `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Columns     map[string]string `gorm:"type:jsonb;serializer:json" json:"columns"` // coluna de origem -> campo do cliente (name, email, phone, address, document, person_type)
+	CreatedAt   time.Time         `json:"created_at"`
+	DeletedAt   gorm.DeletedAt    `gorm:"index" json:"-"`
+}
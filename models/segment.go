@@ -0,0 +1,32 @@
+package models
+
+import (
+	"minha-api/utils"
+	"strings"
+	"time"
+)
+
+// Segment é um grupo dinâmico de clientes salvo pelo filtro, e não pela lista de clientes: os membros são
+// calculados a cada consulta. Filter usa a mesma sintaxe da query string de GET /clients,
+// ex.: "tag=VIP&person_type=PJ&address_parts.uf[in]=RS,SC,PR".
+type Segment struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name        string    `gorm:"uniqueIndex" json:"name"`
+	Description string    `json:"description"`
+	Filter      string    `json:"filter"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate confere os campos do segmento. A expressão do filtro é validada contra os campos de cliente no controller.
+func (s *Segment) Validate() utils.ValidationErrors {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Filter = strings.TrimSpace(s.Filter)
+	v := &utils.Validator{}
+	v.Required("name", s.Name)
+	v.MaxLength("name", s.Name, 100)
+	v.MaxLength("description", s.Description, 500)
+	v.Required("filter", s.Filter)
+	v.MaxLength("filter", s.Filter, 2000)
+	return v.Errors()
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"minha-api/utils"
+	"strings"
+	"time"
+)
+
+// Tag é uma etiqueta livre para agrupar clientes ("VIP", "inadimplente", "região sul").
+// O nome é único sem diferenciar maiúsculas (índice em database.migrations).
+type Tag struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name      string    `gorm:"size:50" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeTagName remove espaços das pontas e repetidos no meio do nome
+func NormalizeTagName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Validate normaliza o nome e aplica as regras da tag
+func (t *Tag) Validate() utils.ValidationErrors {
+	t.Name = NormalizeTagName(t.Name)
+	v := &utils.Validator{}
+	v.Required("name", t.Name)
+	v.MaxLength("name", t.Name, 50)
+	return v.Errors()
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"minha-api/database"
 	"minha-api/models"
+	"minha-api/utils"
 )
 
 type BookRepository struct{}
@@ -17,6 +18,11 @@ func (r *BookRepository) GetAll() ([]models.Book, error) {
 	return books, result.Error
 }
 
+// List retorna uma página de livros conforme filtros, ordenação e paginação (ver BookListSpec)
+func (r *BookRepository) List(q utils.ListQuery) (ListResult[models.Book], error) {
+	return listPage[models.Book](database.DB, q)
+}
+
 func (r *BookRepository) GetByID(id string) (*models.Book, error) {
 	var book models.Book
 	result := database.DB.First(&book, "id = ?", id)
@@ -40,6 +46,7 @@ func (r *BookRepository) Delete(id string) error {
 
 type BookRepositoryInterface interface {
 	GetAll() ([]models.Book, error)
+	List(q utils.ListQuery) (ListResult[models.Book], error)
 	GetByID(id string) (*models.Book, error)
 	Create(book *models.Book) error
 	Update(book *models.Book) error
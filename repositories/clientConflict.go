@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"minha-api/models"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// pgUniqueViolation é o código de erro do Postgres para violação de índice único
+const pgUniqueViolation = "23505"
+
+// clientUniqueIndexes liga os índices únicos parciais de clients (migração 20261019_03) ao campo protegido
+var clientUniqueIndexes = map[string]string{
+	"idx_clients_document_unique": "document",
+	"idx_clients_email_unique":    "email",
+}
+
+// ClientConflictError indica que outro cliente ativo já usa o documento ou o e-mail informado
+type ClientConflictError struct {
+	Field    string `json:"field"`               // document ou email
+	ClientID string `json:"client_id,omitempty"` // cliente que já usa o valor; vazio se não foi possível identificá-lo
+}
+
+func (e *ClientConflictError) Error() string {
+	label := "e-mail"
+	if e.Field == "document" {
+		label = "documento"
+	}
+	if e.ClientID == "" {
+		return fmt.Sprintf("Já existe um cliente com este %s", label)
+	}
+	return fmt.Sprintf("Já existe o cliente %s com este %s", e.ClientID, label)
+}
+
+// ClientConflictFromError identifica em err uma violação dos índices únicos de documento ou e-mail de clients
+// e retorna o conflito, ainda sem o cliente que usa o valor. Outros erros, inclusive violações de outros
+// índices únicos, retornam nil.
+func ClientConflictFromError(err error) *ClientConflictError {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgUniqueViolation {
+		return nil
+	}
+	field, ok := clientUniqueIndexes[pgErr.ConstraintName]
+	if !ok {
+		return nil
+	}
+	return &ClientConflictError{Field: field}
+}
+
+// clientConflict traduz uma violação dos índices únicos de clients em *ClientConflictError, buscando em db
+// qual cliente já usa o valor de algum dos clientes gravados. Outros erros são retornados sem alteração.
+// db não pode ser a transação que falhou: use a conexão de fora dela (ou a transação externa de um savepoint).
+func clientConflict(db *gorm.DB, err error, clients ...models.Client) error {
+	conflict := ClientConflictFromError(err)
+	if conflict == nil {
+		return err
+	}
+	for _, c := range clients {
+		query, value := "document = ?", c.Document
+		if conflict.Field == "email" {
+			query, value = "lower(email) = lower(?)", c.Email
+		}
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		q := db.Model(&models.Client{}).Where(query, value)
+		if c.ID != "" {
+			q = q.Where("id <> ?", c.ID)
+		}
+		var ids []string
+		if q.Limit(1).Pluck("id", &ids).Error == nil && len(ids) > 0 {
+			conflict.ClientID = ids[0]
+			break
+		}
+	}
+	return conflict
+}
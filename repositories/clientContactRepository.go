@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"errors"
+	"minha-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Contacts lista os contatos do cliente, o principal primeiro
+func (r *ClientRepository) Contacts(clientID string) ([]models.ClientContact, error) {
+	var contacts []models.ClientContact
+	err := r.conn().Where("client_id = ?", clientID).Order("\"primary\" DESC, created_at, id").Find(&contacts).Error
+	return contacts, err
+}
+
+// ContactsByClientIDs busca de uma vez os contatos de vários clientes, indexados pelo ID do cliente
+func (r *ClientRepository) ContactsByClientIDs(clientIDs []string) (map[string][]models.ClientContact, error) {
+	byClient := map[string][]models.ClientContact{}
+	if len(clientIDs) == 0 {
+		return byClient, nil
+	}
+	var contacts []models.ClientContact
+	err := r.conn().Where("client_id IN ?", clientIDs).Order("client_id, \"primary\" DESC, created_at, id").Find(&contacts).Error
+	for _, ct := range contacts {
+		byClient[ct.ClientID] = append(byClient[ct.ClientID], ct)
+	}
+	return byClient, err
+}
+
+// GetContact busca um contato do cliente informado
+func (r *ClientRepository) GetContact(clientID, contactID string) (models.ClientContact, error) {
+	var contact models.ClientContact
+	err := r.conn().First(&contact, "id = ? AND client_id = ?", contactID, clientID).Error
+	return contact, err
+}
+
+// SaveContact cria ou atualiza o contato. Se ele for o principal, os demais contatos do cliente deixam de ser
+// e o email e o telefone do cliente passam a ser os do contato, tudo na mesma transação.
+func (r *ClientRepository) SaveContact(contact *models.ClientContact) error {
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(contact).Error; err != nil {
+			return err
+		}
+		if !contact.Primary {
+			return nil
+		}
+		if err := tx.Model(&models.ClientContact{}).
+			Where("client_id = ? AND id <> ? AND \"primary\"", contact.ClientID, contact.ID).
+			Update("primary", false).Error; err != nil {
+			return err
+		}
+		if err := lockClients(tx, contact.ClientID); err != nil {
+			return err
+		}
+		var client models.Client
+		if err := tx.First(&client, "id = ?", contact.ClientID).Error; err != nil {
+			return err
+		}
+		before := client
+		contact.ApplyTo(&client)
+		if err := tx.Model(&client).Select("email", "phone", "phone_e164", "phone_type").Updates(&client).Error; err != nil {
+			return err
+		}
+		return recordRevision(tx, r.audit, models.RevisionUpdated, &before, &client)
+	})
+	return clientConflict(r.conn(), err, models.Client{ID: contact.ClientID, Email: contact.Email})
+}
+
+// DeleteContact exclui o contato. O email e o telefone do cliente são mantidos, mesmo que fosse o principal.
+func (r *ClientRepository) DeleteContact(clientID, contactID string) error {
+	res := r.conn().Where("client_id = ?", clientID).Delete(&models.ClientContact{}, "id = ?", contactID)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// CreateImportedContacts insere contatos vindos de uma importação, associados ao job. Contatos iguais
+// (ver ClientContact.SameAs) a um já cadastrado no cliente, ou repetidos na lista, são ignorados.
+// Retorna quantos foram inseridos.
+func (r *ClientRepository) CreateImportedContacts(jobID string, contacts []models.ClientContact) (int, error) {
+	if len(contacts) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, 0, len(contacts))
+	for _, ct := range contacts {
+		ids = append(ids, ct.ClientID)
+	}
+	known, err := r.ContactsByClientIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+	var inserts []models.ClientContact
+	for _, ct := range contacts {
+		if containsContact(known[ct.ClientID], &ct) {
+			continue
+		}
+		ct.ImportJobID, ct.Primary = jobID, false
+		known[ct.ClientID] = append(known[ct.ClientID], ct)
+		inserts = append(inserts, ct)
+	}
+	if len(inserts) == 0 {
+		return 0, nil
+	}
+	// Em um savepoint quando o repositório está em uma transação, para a falha não abortar a importação inteira
+	err = r.conn().Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(inserts, 500).Error
+	})
+	return len(inserts), err
+}
+
+func containsContact(contacts []models.ClientContact, ct *models.ClientContact) bool {
+	for i := range contacts {
+		if contacts[i].SameAs(ct) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncPrimaryContact faz o contato principal do cliente espelhar o email e o telefone gravados nele, criando o
+// contato se ainda não existir. Um cliente que ficou sem email e sem telefone deixa de ter contato principal
+// (o contato é mantido com os dados antigos). jobID associa o contato criado a uma importação, para ser
+// excluído se ela for desfeita.
+func syncPrimaryContact(tx *gorm.DB, client *models.Client, jobID string) error {
+	var contact models.ClientContact
+	err := tx.Where("client_id = ? AND \"primary\"", client.ID).First(&contact).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !hasContactInfo(client) {
+			return nil
+		}
+		contact = models.ClientContact{ClientID: client.ID, Type: models.ContactOther, Primary: true, ImportJobID: jobID}
+		contact.SyncFrom(client)
+		return tx.Create(&contact).Error
+	}
+	if err != nil {
+		return err
+	}
+	if !hasContactInfo(client) {
+		return tx.Model(&contact).Update("primary", false).Error
+	}
+	if !contact.SyncFrom(client) {
+		return nil
+	}
+	return tx.Model(&contact).Select("email", "phone", "phone_e164").Updates(&contact).Error
+}
+
+// createPrimaryContacts cria em lote o contato principal de clientes recém-inseridos que têm email ou telefone
+func createPrimaryContacts(tx *gorm.DB, clients []models.Client, jobID string, batchSize int) error {
+	var contacts []models.ClientContact
+	for i := range clients {
+		if !hasContactInfo(&clients[i]) {
+			continue
+		}
+		contact := models.ClientContact{ClientID: clients[i].ID, Type: models.ContactOther, Primary: true, ImportJobID: jobID}
+		contact.SyncFrom(&clients[i])
+		contacts = append(contacts, contact)
+	}
+	if len(contacts) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(contacts, batchSize).Error
+}
+
+func hasContactInfo(client *models.Client) bool {
+	return strings.TrimSpace(client.Email) != "" || strings.TrimSpace(client.Phone) != ""
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"minha-api/models"
+)
+
+// duplicateCandidatesSQL gera no banco os pares de clientes ativos com documento, email ou telefone iguais.
+// Cada par aparece uma vez, com o menor ID em a_id.
+const duplicateCandidatesSQL = `
+	SELECT a.id AS a_id, b.id AS b_id FROM clients a
+		JOIN clients b ON b.document = a.document AND b.id > a.id AND b.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL AND a.document <> ''
+	UNION
+	SELECT a.id, b.id FROM clients a
+		JOIN clients b ON lower(b.email) = lower(a.email) AND b.id > a.id AND b.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL AND a.email <> ''
+	UNION
+	SELECT a.id, b.id FROM clients a
+		JOIN clients b ON b.phone_e164 = a.phone_e164 AND b.id > a.id AND b.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL AND a.phone_e164 <> ''`
+
+// duplicateNameCandidatesSQL acrescenta os pares com nomes parecidos pelo pg_trgm (operador %, limite
+// pg_trgm.similarity_threshold), usando o índice idx_clients_name_trgm
+const duplicateNameCandidatesSQL = `
+	UNION
+	SELECT a.id, b.id FROM clients a
+		JOIN clients b ON immutable_unaccent(lower(b.name)) % immutable_unaccent(lower(a.name)) AND b.id > a.id AND b.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL`
+
+// DuplicateCandidates percorre os pares de clientes candidatos a duplicidade, gerados no banco sem carregar a
+// tabela inteira: documento, email ou telefone iguais e, com byName, também nomes parecidos. Os pares são
+// entregues a fn em lotes de até batchSize, já com os dados dos dois clientes. Nenhum candidato é descartado.
+func (r *ClientRepository) DuplicateCandidates(byName bool, batchSize int, fn func(pairs [][2]models.Client) error) error {
+	query := duplicateCandidatesSQL
+	if byName {
+		query += duplicateNameCandidatesSQL
+	}
+	rows, err := r.conn().Raw(query).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	ids := make([][2]string, 0, batchSize)
+	for rows.Next() {
+		var pair [2]string
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			return err
+		}
+		if ids = append(ids, pair); len(ids) == batchSize {
+			if err := r.duplicatePairs(ids, fn); err != nil {
+				return err
+			}
+			ids = ids[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.duplicatePairs(ids, fn)
+}
+
+// duplicatePairs carrega de uma vez os clientes de um lote de pares e chama fn
+func (r *ClientRepository) duplicatePairs(ids [][2]string, fn func(pairs [][2]models.Client) error) error {
+	unique := make([]string, 0, 2*len(ids))
+	for _, pair := range ids {
+		unique = append(unique, pair[0], pair[1])
+	}
+	clients, err := r.findIn("id IN ?", unique)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]models.Client, len(clients))
+	for _, c := range clients {
+		byID[c.ID] = c
+	}
+	pairs := make([][2]models.Client, 0, len(ids))
+	for _, pair := range ids {
+		a, okA := byID[pair[0]]
+		b, okB := byID[pair[1]]
+		if okA && okB { // excluído depois da consulta dos pares
+			pairs = append(pairs, [2]models.Client{a, b})
+		}
+	}
+	return fn(pairs)
+}
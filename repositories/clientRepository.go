@@ -1,62 +1,493 @@
 package repositories
 
 import (
+	"errors"
 	"minha-api/database"
 	"minha-api/models"
+	"minha-api/utils"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type ClientRepository struct{}
+// ClientRepository grava e consulta clientes. Toda criação, alteração e exclusão de cliente fica registrada
+// no histórico (models.ClientRevision), em nome do autor informado em WithAudit.
+type ClientRepository struct {
+	db    *gorm.DB // conexão ou transação usada; nil usa database.DB
+	audit Audit
+}
 
 func NewClientRepository() *ClientRepository {
 	return &ClientRepository{}
 }
 
+// WithTx retorna um repositório que executa as operações dentro da transação informada
+func (r *ClientRepository) WithTx(tx *gorm.DB) *ClientRepository {
+	return &ClientRepository{db: tx, audit: r.audit}
+}
+
+// Transaction executa fn em uma transação: se fn retornar erro tudo é desfeito, senão é confirmado
+func (r *ClientRepository) Transaction(fn func(tx *ClientRepository) error) error {
+	return r.conn().Transaction(func(tx *gorm.DB) error {
+		return fn(r.WithTx(tx))
+	})
+}
+
+func (r *ClientRepository) conn() *gorm.DB {
+	if r.db != nil {
+		return r.db
+	}
+	return database.DB
+}
+
 func (r *ClientRepository) Create(client *models.Client) error {
-	return database.DB.Create(client).Error
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(client).Error; err != nil {
+			return err
+		}
+		if err := syncPrimaryContact(tx, client, ""); err != nil {
+			return err
+		}
+		return recordRevision(tx, r.audit, models.RevisionCreated, nil, client)
+	})
+	return clientConflict(r.conn(), err, *client)
+}
+
+// CreateInBatches insere os clientes em lotes de até batchSize registros por comando INSERT
+func (r *ClientRepository) CreateInBatches(clients []models.Client, batchSize int) error {
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(clients, batchSize).Error; err != nil {
+			return err
+		}
+		if err := createPrimaryContacts(tx, clients, "", batchSize); err != nil {
+			return err
+		}
+		return recordCreatedRevisions(tx, r.audit, clients, batchSize)
+	})
+	return clientConflict(r.conn(), err, clients...)
+}
+
+// CreateImported insere clientes vindos de uma importação e registra cada um no histórico do job.
+// Tudo roda em uma transação (ou em um savepoint, se o repositório já estiver em uma).
+func (r *ClientRepository) CreateImported(jobID string, clients []models.Client, lines []int, batchSize int) error {
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(clients, batchSize).Error; err != nil {
+			return err
+		}
+		changes := make([]models.ImportJobChange, len(clients))
+		for i := range clients {
+			changes[i] = models.ImportJobChange{ImportJobID: jobID, ClientID: clients[i].ID, Action: models.ImportActionInserted, Line: lines[i]}
+		}
+		if err := tx.CreateInBatches(changes, batchSize).Error; err != nil {
+			return err
+		}
+		if err := createPrimaryContacts(tx, clients, jobID, batchSize); err != nil {
+			return err
+		}
+		return recordCreatedRevisions(tx, r.importAudit(jobID), clients, batchSize)
+	})
+	return clientConflict(r.conn(), err, clients...)
+}
+
+// SaveImported atualiza por uma importação o cliente clientID e guarda no histórico do job os dados anteriores.
+// Com o cliente bloqueado, relê os dados atuais e aplica apply sobre eles, de modo que gravações concorrentes
+// não se percam; os dados relidos são os anteriores do histórico e a base da revisão. Um erro de apply desfaz
+// a gravação e é retornado como está. Retorna o cliente gravado.
+func (r *ClientRepository) SaveImported(jobID, clientID string, line int, apply func(client *models.Client) error) (models.Client, error) {
+	var client models.Client
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := lockClients(tx, clientID); err != nil {
+			return err
+		}
+		var previous models.Client
+		if err := tx.First(&previous, "id = ?", clientID).Error; err != nil {
+			return err
+		}
+		client = previous
+		client.ImportJobID = jobID
+		if err := apply(&client); err != nil {
+			return err
+		}
+		if err := tx.Save(&client).Error; err != nil {
+			return err
+		}
+		change := models.ImportJobChange{ImportJobID: jobID, ClientID: client.ID, Action: models.ImportActionUpdated, Line: line, Previous: &previous}
+		if err := tx.Create(&change).Error; err != nil {
+			return err
+		}
+		if err := syncPrimaryContact(tx, &client, jobID); err != nil {
+			return err
+		}
+		return recordRevision(tx, r.importAudit(jobID), models.RevisionUpdated, &previous, &client)
+	})
+	return client, clientConflict(r.conn(), err, client)
+}
+
+// importAudit é o autor das gravações de uma importação, com o job associado
+func (r *ClientRepository) importAudit(jobID string) Audit {
+	a := r.audit
+	a.ImportJobID = jobID
+	return a
 }
 
 func (r *ClientRepository) GetAll() ([]models.Client, error) {
 	var clients []models.Client
-	err := database.DB.Find(&clients).Error
+	err := r.conn().Find(&clients).Error
+	return clients, err
+}
+
+// ClientFilter reúne os filtros aceitos na listagem de clientes
+type ClientFilter struct {
+	PhoneE164   string // telefone normalizado, comparado por igualdade
+	PhoneDigits string // trecho de dígitos do telefone, para buscas parciais
+	City        string // comparada sem diferenciar maiúsculas
+	UF          string
+	CEP         string   // somente dígitos
+	ImportJobID string   // clientes criados ou alterados por último nessa importação
+	Tags        []string // clientes com todas essas tags (nomes, sem diferenciar maiúsculas)
+	WithoutTags []string // clientes sem nenhuma dessas tags
+}
+
+// Find lista os clientes que atendem ao filtro
+func (r *ClientRepository) Find(filter ClientFilter) ([]models.Client, error) {
+	var clients []models.Client
+	err := r.filtered(filter).Find(&clients).Error
 	return clients, err
 }
 
+// List retorna uma página dos clientes que atendem ao filtro, conforme a consulta de listagem (ver ClientListSpec)
+func (r *ClientRepository) List(filter ClientFilter, q utils.ListQuery) (ListResult[models.Client], error) {
+	result, err := listPage[models.Client](r.filtered(filter), q)
+	if err == nil {
+		err = r.LoadTags(result.Items)
+	}
+	return result, err
+}
+
+// Count conta os clientes que atendem ao filtro
+func (r *ClientRepository) Count(filter ClientFilter) (int64, error) {
+	var count int64
+	err := r.filtered(filter).Model(&models.Client{}).Count(&count).Error
+	return count, err
+}
+
+// FindInBatches percorre os clientes que atendem ao filtro em lotes de até batchSize, em ordem de ID,
+// sem carregar todos em memória. Se fn retornar erro a leitura é interrompida e o erro é devolvido.
+func (r *ClientRepository) FindInBatches(filter ClientFilter, batchSize int, fn func(clients []models.Client) error) error {
+	var clients []models.Client
+	return r.filtered(filter).FindInBatches(&clients, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(clients)
+	}).Error
+}
+
+func (r *ClientRepository) filtered(filter ClientFilter) *gorm.DB {
+	db := r.conn()
+	if filter.PhoneE164 != "" {
+		db = db.Where("phone_e164 = ?", filter.PhoneE164)
+	}
+	if filter.PhoneDigits != "" {
+		db = db.Where("phone_e164 LIKE ?", "%"+filter.PhoneDigits+"%")
+	}
+	if filter.City != "" {
+		db = db.Where("address_city ILIKE ?", filter.City)
+	}
+	if filter.UF != "" {
+		db = db.Where("address_uf = ?", filter.UF)
+	}
+	if filter.CEP != "" {
+		db = db.Where("address_cep = ?", filter.CEP)
+	}
+	if filter.ImportJobID != "" {
+		db = db.Where("import_job_id = ?", filter.ImportJobID)
+	}
+	for _, tag := range filter.Tags {
+		db = db.Where("EXISTS ("+clientHasTag+")", tag)
+	}
+	for _, tag := range filter.WithoutTags {
+		db = db.Where("NOT EXISTS ("+clientHasTag+")", tag)
+	}
+	return db
+}
+
+// clientHasTag é a subconsulta que verifica se o cliente da consulta externa tem a tag informada
+const clientHasTag = `SELECT 1 FROM client_tags JOIN tags ON tags.id = client_tags.tag_id
+	WHERE client_tags.client_id = clients.id AND lower(tags.name) = lower(?)`
+
+// LoadTags preenche as tags dos clientes com uma única consulta
+func (r *ClientRepository) LoadTags(clients []models.Client) error {
+	ids := make([]string, len(clients))
+	for i := range clients {
+		ids[i] = clients[i].ID
+	}
+	tags, err := tagsByClientIDs(r.conn(), ids)
+	if err != nil {
+		return err
+	}
+	for i := range clients {
+		clients[i].Tags = tags[clients[i].ID]
+	}
+	return nil
+}
+
+// ClientSearchResult é um cliente encontrado pela busca, com a relevância calculada pelo banco
+type ClientSearchResult struct {
+	models.Client
+	Rank float64 `json:"rank"`
+}
+
+// Expressões indexadas pela migração de busca (database/migrations.go): precisam ser idênticas às dos índices
+const (
+	clientSearchName = "immutable_unaccent(lower(name))"
+	clientSearchTSV  = "to_tsvector('simple', immutable_unaccent(lower(coalesce(name, '') || ' ' || coalesce(email, ''))))"
+)
+
+// clientSearchMinDigits é a quantidade mínima de dígitos para buscar em telefone e CPF/CNPJ
+const clientSearchMinDigits = 3
+
+// Search busca clientes por trecho do nome (sem diferenciar acentos e tolerando erros de digitação), do email
+// e dos dígitos do telefone ou do CPF/CNPJ. Os resultados vêm do mais para o menos relevante.
+func (r *ClientRepository) Search(q string, filter ClientFilter, limit int) ([]ClientSearchResult, error) {
+	term := strings.Join(utils.SearchWords(q), " ")
+	like := "%" + utils.EscapeLike(term) + "%"
+	email := strings.ToLower(strings.TrimSpace(q))
+	emailLike := "%" + utils.EscapeLike(email) + "%"
+
+	conds := []string{clientSearchName + " % ?", clientSearchName + " LIKE ?", "lower(email) LIKE ?"}
+	condArgs := []interface{}{term, like, emailLike}
+	ranks := []string{
+		"similarity(" + clientSearchName + ", ?)",
+		"CASE WHEN lower(email) = ? THEN 1 WHEN lower(email) LIKE ? THEN 0.5 ELSE 0 END",
+	}
+	rankArgs := []interface{}{term, email, emailLike}
+
+	if tsq := utils.PrefixTSQuery(q); tsq != "" {
+		conds = append(conds, clientSearchTSV+" @@ to_tsquery('simple', ?)")
+		condArgs = append(condArgs, tsq)
+		ranks = append(ranks, "ts_rank("+clientSearchTSV+", to_tsquery('simple', ?))")
+		rankArgs = append(rankArgs, tsq)
+	}
+	if digits := utils.OnlyDigits(q); len(digits) >= clientSearchMinDigits {
+		digitsLike := "%" + digits + "%"
+		conds = append(conds, "document LIKE ?", "phone_e164 LIKE ?")
+		condArgs = append(condArgs, digitsLike, digitsLike)
+		ranks = append(ranks, "CASE WHEN document = ? OR phone_e164 LIKE ? THEN 1 WHEN document LIKE ? OR phone_e164 LIKE ? THEN 0.5 ELSE 0 END")
+		rankArgs = append(rankArgs, digits, "%"+digits, digitsLike, digitsLike)
+	}
+
+	var results []ClientSearchResult
+	err := r.filtered(filter).Model(&models.Client{}).
+		Select("clients.*, ("+strings.Join(ranks, " + ")+") AS rank", rankArgs...).
+		Where("("+strings.Join(conds, " OR ")+")", condArgs...).
+		Order("rank DESC, name").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+// GetByID busca o cliente pelo ID. IDs de clientes removidos em uma mesclagem levam ao cliente que ficou.
 func (r *ClientRepository) GetByID(id string) (models.Client, error) {
 	var client models.Client
-	err := database.DB.First(&client, "id = ?", id).Error
+	err := r.conn().First(&client, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var alias models.ClientAlias
+		if r.conn().First(&alias, "alias_id = ?", id).Error == nil {
+			err = r.conn().First(&client, "id = ?", alias.ClientID).Error
+		}
+	}
 	return client, err
 }
 
+// Merge grava o cliente mesclado, exclui o cliente removido e registra o ID dele como alias do que ficou.
+// Aliases, contatos e tags do cliente removido passam para o mesclado.
+func (r *ClientRepository) Merge(merged *models.Client, removeID string) error {
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := lockClients(tx, merged.ID, removeID); err != nil {
+			return err
+		}
+		var before, removed models.Client
+		if err := tx.First(&before, "id = ?", merged.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&removed, "id = ?", removeID).Error; err != nil {
+			return err
+		}
+		// O removido sai antes de gravar o mesclado, que pode herdar dele o documento ou o e-mail (índices únicos)
+		if err := tx.Delete(&models.Client{}, "id = ?", removeID).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(merged).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, r.audit, models.RevisionMerged, &before, merged); err != nil {
+			return err
+		}
+		if err := recordRevision(tx, r.audit, models.RevisionDeleted, &removed, nil); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ClientAlias{}).Where("client_id = ?", removeID).Update("client_id", merged.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`INSERT INTO client_tags (client_id, tag_id)
+			SELECT ?, tag_id FROM client_tags WHERE client_id = ? ON CONFLICT DO NOTHING`, merged.ID, removeID).Error; err != nil {
+			return err
+		}
+		// Os contatos do removido passam para o mesclado; o principal continua sendo o do cliente que ficou
+		if err := tx.Model(&models.ClientContact{}).Where("client_id = ?", removeID).
+			Updates(map[string]interface{}{"client_id": merged.ID, "primary": false}).Error; err != nil {
+			return err
+		}
+		if err := syncPrimaryContact(tx, merged, ""); err != nil {
+			return err
+		}
+		return tx.Create(&models.ClientAlias{AliasID: removeID, ClientID: merged.ID}).Error
+	})
+	return clientConflict(r.conn(), err, *merged)
+}
+
+// Update substitui todos os campos do cliente pelos informados: campos vazios limpam o valor atual.
+// A importação de origem e as tags são mantidas. Retorna gorm.ErrRecordNotFound se o cliente não existe.
 func (r *ClientRepository) Update(client *models.Client) error {
-	return database.DB.Model(&models.Client{}).Where("id = ?", client.ID).Updates(client).Error
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := lockClients(tx, client.ID); err != nil {
+			return err
+		}
+		var before models.Client
+		if err := tx.First(&before, "id = ?", client.ID).Error; err != nil {
+			return err
+		}
+		client.ImportJobID = before.ImportJobID
+		if err := tx.Omit(clause.Associations).Save(client).Error; err != nil {
+			return err
+		}
+		if err := syncPrimaryContact(tx, client, ""); err != nil {
+			return err
+		}
+		return recordRevision(tx, r.audit, models.RevisionUpdated, &before, client)
+	})
+	return clientConflict(r.conn(), err, *client)
 }
 
+// Save grava todos os campos do cliente, inclusive os vazios
+func (r *ClientRepository) Save(client *models.Client) error {
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := lockClients(tx, client.ID); err != nil {
+			return err
+		}
+		var before models.Client
+		if err := tx.First(&before, "id = ?", client.ID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := tx.Save(client).Error; err != nil {
+			return err
+		}
+		if err := syncPrimaryContact(tx, client, ""); err != nil {
+			return err
+		}
+		if before.ID == "" {
+			return recordRevision(tx, r.audit, models.RevisionCreated, nil, client)
+		}
+		return recordRevision(tx, r.audit, models.RevisionUpdated, &before, client)
+	})
+	return clientConflict(r.conn(), err, *client)
+}
+
+// Delete exclui o cliente (exclusão lógica). Um ID inexistente não é erro.
 func (r *ClientRepository) Delete(id string) error {
-	return database.DB.Delete(&models.Client{}, "id = ?", id).Error
+	return r.conn().Transaction(func(tx *gorm.DB) error {
+		if err := lockClients(tx, id); err != nil {
+			return err
+		}
+		var before models.Client
+		err := tx.First(&before, "id = ?", id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Client{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return recordRevision(tx, r.audit, models.RevisionDeleted, &before, nil)
+	})
 }
 
-func (r *ClientRepository) ExistsByNameAndCPF(name, cpf string) (bool, error) {
+// ExistsByNameAndDocument verifica duplicidade por nome e CPF/CNPJ (somente dígitos)
+func (r *ClientRepository) ExistsByNameAndDocument(name, document string) (bool, error) {
 	var count int64
-	type Result struct {
-		Name string
-		CPF  string
-	}
-	// Se o campo CPF não existir no model, sempre retorna false
-	db := database.DB.Table("clients").Where("name = ? AND cpf = ?", name, cpf)
+	db := r.conn().Model(&models.Client{}).Where("name = ? AND document = ?", name, document)
 	db.Count(&count)
 	return count > 0, db.Error
 }
 
 func (r *ClientRepository) ExistsByNameAndCNPJ(name, cnpj string) (bool, error) {
 	var count int64
-	db := database.DB.Table("clients").Where("name = ? AND cnpj = ?", name, cnpj)
+	db := r.conn().Table("clients").Where("name = ? AND cnpj = ?", name, cnpj)
 	db.Count(&count)
 	return count > 0, db.Error
 }
 
 func (r *ClientRepository) ExistsByNameAndEmail(name, email string) (bool, error) {
 	var count int64
-	db := database.DB.Table("clients").Where("name = ? AND email = ?", name, email)
+	db := r.conn().Table("clients").Where("name = ? AND email = ?", name, email)
 	db.Count(&count)
 	return count > 0, db.Error
 }
+
+// FindByNameAndDocument retorna o cliente com o nome e CPF/CNPJ (somente dígitos) informados, ou nil se não existir
+func (r *ClientRepository) FindByNameAndDocument(name, document string) (*models.Client, error) {
+	return r.findOne("name = ? AND document = ?", name, document)
+}
+
+// FindByDocument retorna o cliente com o CPF/CNPJ (somente dígitos) informado, ou nil se não existir
+func (r *ClientRepository) FindByDocument(document string) (*models.Client, error) {
+	return r.findOne("document = ?", document)
+}
+
+// FindByEmail retorna o cliente com o email informado (sem diferenciar maiúsculas), ou nil se não existir
+func (r *ClientRepository) FindByEmail(email string) (*models.Client, error) {
+	return r.findOne("LOWER(email) = LOWER(?)", email)
+}
+
+// FindByNameAndEmail retorna o cliente com o nome e email informados, ou nil se não existir
+func (r *ClientRepository) FindByNameAndEmail(name, email string) (*models.Client, error) {
+	return r.findOne("name = ? AND email = ?", name, email)
+}
+
+// FindByDocuments retorna os clientes com qualquer um dos CPF/CNPJ (somente dígitos) informados
+func (r *ClientRepository) FindByDocuments(documents []string) ([]models.Client, error) {
+	return r.findIn("document IN ?", documents)
+}
+
+// FindByEmails retorna os clientes com qualquer um dos emails informados (em minúsculas)
+func (r *ClientRepository) FindByEmails(emails []string) ([]models.Client, error) {
+	return r.findIn("LOWER(email) IN ?", emails)
+}
+
+// FindByNameAndDocumentPairs retorna os clientes que correspondem a algum dos pares {nome, documento}
+func (r *ClientRepository) FindByNameAndDocumentPairs(pairs [][]interface{}) ([]models.Client, error) {
+	return r.findIn("(name, document) IN ?", pairs)
+}
+
+// FindByNameAndEmailPairs retorna os clientes que correspondem a algum dos pares {nome, email}
+func (r *ClientRepository) FindByNameAndEmailPairs(pairs [][]interface{}) ([]models.Client, error) {
+	return r.findIn("(name, email) IN ?", pairs)
+}
+
+func (r *ClientRepository) findIn(query string, values interface{}) ([]models.Client, error) {
+	var clients []models.Client
+	err := r.conn().Where(query, values).Find(&clients).Error
+	return clients, err
+}
+
+func (r *ClientRepository) findOne(query string, args ...interface{}) (*models.Client, error) {
+	var clients []models.Client
+	if err := r.conn().Where(query, args...).Limit(1).Find(&clients).Error; err != nil {
+		return nil, err
+	}
+	if len(clients) == 0 {
+		return nil, nil
+	}
+	return &clients[0], nil
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"minha-api/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrRevisionNotRestorable indica uma revisão de exclusão, que não tem dados novos para restaurar
+var ErrRevisionNotRestorable = errors.New("revisão de exclusão não pode ser restaurada")
+
+// ErrRevisionInvalid indica uma revisão cujos dados não passam mais na validação (ex.: documento inválido)
+var ErrRevisionInvalid = errors.New("dados da revisão inválidos")
+
+// Audit identifica quem fez as alterações e em qual requisição, para o histórico dos clientes
+type Audit struct {
+	Actor       string
+	RequestID   string
+	ImportJobID string // preenchido pelo repositório nas gravações de uma importação
+}
+
+// auditSystemActor é o autor das alterações feitas fora de uma requisição (jobs, testes)
+const auditSystemActor = "sistema"
+
+// WithAudit retorna um repositório que registra as alterações de clientes em nome do autor informado
+func (r *ClientRepository) WithAudit(a Audit) *ClientRepository {
+	return &ClientRepository{db: r.db, audit: a}
+}
+
+// History lista as revisões do cliente, da mais recente para a mais antiga. Funciona também para clientes excluídos.
+func (r *ClientRepository) History(clientID string) ([]models.ClientRevision, error) {
+	var revisions []models.ClientRevision
+	err := r.conn().Where("client_id = ?", clientID).Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// Revision busca uma revisão do cliente pelo número da versão
+func (r *ClientRepository) Revision(clientID string, version int) (models.ClientRevision, error) {
+	var rev models.ClientRevision
+	err := r.conn().First(&rev, "client_id = ? AND version = ?", clientID, version).Error
+	return rev, err
+}
+
+// Restore volta os campos do cliente aos da revisão informada, registrando uma nova revisão "restored".
+// Um cliente excluído volta a existir. Retorna o cliente restaurado.
+func (r *ClientRepository) Restore(clientID string, version int) (models.Client, error) {
+	var client models.Client
+	err := r.conn().Transaction(func(tx *gorm.DB) error {
+		var rev models.ClientRevision
+		if err := tx.First(&rev, "client_id = ? AND version = ?", clientID, version).Error; err != nil {
+			return err
+		}
+		if rev.Action == models.RevisionDeleted || rev.Snapshot == nil {
+			return ErrRevisionNotRestorable
+		}
+		if err := lockClients(tx, clientID); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().First(&client, "id = ?", clientID).Error; err != nil {
+			return err
+		}
+		before := client
+		models.MergeClientFields(&client, rev.Snapshot, true)
+		// Campos derivados (telefone normalizado, CNPJ legado) acompanham os restaurados
+		client.NormalizePhone()
+		if err := client.NormalizeDocument(); err != nil {
+			return fmt.Errorf("%w: %v", ErrRevisionInvalid, err)
+		}
+		client.DeletedAt = gorm.DeletedAt{}
+		if err := tx.Unscoped().Save(&client).Error; err != nil {
+			return err
+		}
+		if err := syncPrimaryContact(tx, &client, ""); err != nil {
+			return err
+		}
+		return recordRevision(tx, r.audit, models.RevisionRestored, &before, &client)
+	})
+	return client, clientConflict(r.conn(), err, client)
+}
+
+// lockClients bloqueia as linhas dos clientes (inclusive excluídos) até o fim da transação, em ordem de ID
+// para duas transações não se travarem. Quem grava revisões bloqueia o cliente antes de ler o estado anterior:
+// assim as versões calculadas por recordRevision não colidem entre gravações concorrentes.
+func lockClients(tx *gorm.DB, ids ...string) error {
+	return tx.Exec("SELECT 1 FROM clients WHERE id IN ? ORDER BY id FOR UPDATE", ids).Error
+}
+
+// recordRevision grava a próxima versão do cliente com as diferenças entre before e after.
+// Em criações before é nil; em exclusões after é nil e o snapshot guarda o estado anterior.
+// O cliente precisa estar bloqueado na transação (ver lockClients).
+func recordRevision(tx *gorm.DB, audit Audit, action string, before, after *models.Client) error {
+	var empty models.Client
+	snapshot := after
+	if before == nil {
+		before = &empty
+	}
+	if after == nil {
+		snapshot, after = before, &empty
+	}
+	changes := models.DiffClients(before, after)
+	if len(changes) == 0 && action == models.RevisionUpdated {
+		return nil // nada mudou nos campos do cliente
+	}
+	var last int
+	if err := tx.Model(&models.ClientRevision{}).Where("client_id = ?", snapshot.ID).
+		Select("COALESCE(MAX(version), 0)").Scan(&last).Error; err != nil {
+		return err
+	}
+	rev := newRevision(audit, action, snapshot, changes)
+	rev.Version = last + 1
+	return tx.Create(&rev).Error
+}
+
+// recordCreatedRevisions grava a primeira versão de clientes recém-inseridos, em lote
+func recordCreatedRevisions(tx *gorm.DB, audit Audit, clients []models.Client, batchSize int) error {
+	if len(clients) == 0 {
+		return nil
+	}
+	var empty models.Client
+	revisions := make([]models.ClientRevision, len(clients))
+	for i := range clients {
+		revisions[i] = newRevision(audit, models.RevisionCreated, &clients[i], models.DiffClients(&empty, &clients[i]))
+		revisions[i].Version = 1
+	}
+	return tx.CreateInBatches(revisions, batchSize).Error
+}
+
+func newRevision(audit Audit, action string, snapshot *models.Client, changes []models.FieldChange) models.ClientRevision {
+	copied := *snapshot
+	copied.Tags = nil // tags não fazem parte da versão do cliente
+	actor := audit.Actor
+	if actor == "" {
+		actor = auditSystemActor
+	}
+	return models.ClientRevision{
+		ClientID:    snapshot.ID,
+		Action:      action,
+		Changes:     changes,
+		Snapshot:    &copied,
+		Actor:       actor,
+		RequestID:   audit.RequestID,
+		ImportJobID: audit.ImportJobID,
+	}
+}
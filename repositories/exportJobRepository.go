@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"minha-api/database"
+	"minha-api/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExportJobRepository struct{}
+
+func NewExportJobRepository() *ExportJobRepository {
+	return &ExportJobRepository{}
+}
+
+func (r *ExportJobRepository) Create(job *models.ExportJob) error {
+	return database.DB.Create(job).Error
+}
+
+func (r *ExportJobRepository) GetByID(id string) (models.ExportJob, error) {
+	var job models.ExportJob
+	err := database.DB.First(&job, "id = ?", id).Error
+	return job, err
+}
+
+// ClaimNext marca como em andamento o job pendente mais antigo e o retorna, ou nil se não houver nenhum.
+// Jobs bloqueados por outra instância da API são pulados, então várias instâncias podem processar a fila.
+func (r *ExportJobRepository) ClaimNext() (*models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.ExportJobPending).Order("created_at").Limit(1).Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+		now := time.Now()
+		jobs[0].Status = models.ExportJobRunning
+		jobs[0].StartedAt = &now
+		return tx.Model(&jobs[0]).Updates(map[string]interface{}{"status": jobs[0].Status, "started_at": now}).Error
+	})
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+// RequeueStale devolve à fila os jobs em andamento sem progresso desde before (ex.: a API caiu no meio da exportação)
+func (r *ExportJobRepository) RequeueStale(before time.Time) (int64, error) {
+	res := database.DB.Model(&models.ExportJob{}).
+		Where("status = ? AND updated_at < ?", models.ExportJobRunning, before).
+		Updates(map[string]interface{}{"status": models.ExportJobPending, "processed": 0})
+	return res.RowsAffected, res.Error
+}
+
+// UpdateProgress grava o total e a quantidade de clientes já exportados
+func (r *ExportJobRepository) UpdateProgress(id string, total, processed int) error {
+	return database.DB.Model(&models.ExportJob{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"total": total, "processed": processed}).Error
+}
+
+// Finish marca o job como concluído, com o arquivo gerado e a data em que ele expira
+func (r *ExportJobRepository) Finish(job *models.ExportJob, fileKey string, expiresAt time.Time) error {
+	now := time.Now()
+	job.Status = models.ExportJobDone
+	job.FileKey = fileKey
+	job.FinishedAt = &now
+	job.ExpiresAt = &expiresAt
+	return database.DB.Model(job).Updates(map[string]interface{}{
+		"status": job.Status, "file_key": fileKey, "processed": job.Processed, "finished_at": now, "expires_at": expiresAt,
+	}).Error
+}
+
+// Fail marca o job como falho, guardando a mensagem de erro
+func (r *ExportJobRepository) Fail(job *models.ExportJob, message string) error {
+	now := time.Now()
+	job.Status = models.ExportJobFailed
+	job.Error = message
+	job.FinishedAt = &now
+	return database.DB.Model(job).Updates(map[string]interface{}{"status": job.Status, "error": message, "finished_at": now}).Error
+}
+
+// FindExpired lista os jobs concluídos cujo arquivo já passou da validade
+func (r *ExportJobRepository) FindExpired(now time.Time) ([]models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := database.DB.Where("status = ? AND expires_at < ?", models.ExportJobDone, now).Find(&jobs).Error
+	return jobs, err
+}
+
+// MarkExpired registra que o arquivo do job foi removido
+func (r *ExportJobRepository) MarkExpired(id string) error {
+	return database.DB.Model(&models.ExportJob{}).Where("id = ?", id).Update("status", models.ExportJobExpired).Error
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"minha-api/database"
 	"minha-api/models"
+	"minha-api/utils"
 )
 
 type FileProcessRepository struct{}
@@ -17,6 +18,11 @@ func (r *FileProcessRepository) GetAll() ([]models.FileProcess, error) {
 	return files, result.Error
 }
 
+// List retorna uma página de arquivos conforme filtros, ordenação e paginação (ver FileProcessListSpec)
+func (r *FileProcessRepository) List(q utils.ListQuery) (ListResult[models.FileProcess], error) {
+	return listPage[models.FileProcess](database.DB, q)
+}
+
 func (r *FileProcessRepository) GetByID(id string) (*models.FileProcess, error) {
 	var f models.FileProcess
 	result := database.DB.First(&f, "id = ?", id)
@@ -40,6 +46,7 @@ func (r *FileProcessRepository) Delete(id string) error {
 
 type FileProcessRepositoryInterface interface {
 	GetAll() ([]models.FileProcess, error)
+	List(q utils.ListQuery) (ListResult[models.FileProcess], error)
 	GetByID(id string) (*models.FileProcess, error)
 	Create(f *models.FileProcess) error
 	Update(f *models.FileProcess) error
@@ -3,11 +3,14 @@ package repositories
 import (
 	"errors"
 	"minha-api/models"
+	"minha-api/utils"
+	"sort"
 	"time"
 )
 
 type FileProcessRepositoryMock struct {
-	Files map[string]models.FileProcess
+	Files         map[string]models.FileProcess
+	LastListQuery utils.ListQuery // última consulta recebida por List
 }
 
 // Garante que FileProcessRepositoryMock implementa FileProcessRepositoryInterface
@@ -29,6 +32,18 @@ func (m *FileProcessRepositoryMock) GetAll() ([]models.FileProcess, error) {
 	return files, nil
 }
 
+// List ignora filtros e ordenação (registrados em LastListQuery) e pagina por offset, em ordem de ID
+func (m *FileProcessRepositoryMock) List(q utils.ListQuery) (ListResult[models.FileProcess], error) {
+	m.LastListQuery = q
+	items, _ := m.GetAll()
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	result := ListResult[models.FileProcess]{Total: int64(len(items))}
+	start := min(q.Offset, len(items))
+	end := min(start+q.Limit, len(items))
+	result.Items = items[start:end]
+	return result, nil
+}
+
 func (m *FileProcessRepositoryMock) GetByID(id string) (*models.FileProcess, error) {
 	if f, ok := m.Files[id]; ok {
 		return &f, nil
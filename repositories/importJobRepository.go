@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"errors"
+	"minha-api/database"
+	"minha-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrImportJobNotRollbackable indica um job que não pode ser desfeito (em andamento, cancelado ou já desfeito)
+var ErrImportJobNotRollbackable = errors.New("importação não pode ser desfeita")
+
+// ImportRollbackSkip é um cliente que não foi revertido porque foi alterado depois da importação
+type ImportRollbackSkip struct {
+	ClientID string `json:"client_id"`
+	Reason   string `json:"reason"`
+}
+
+// ImportRollbackResult resume o que foi desfeito de um job de importação
+type ImportRollbackResult struct {
+	Deleted  int                  `json:"deleted"`
+	Restored int                  `json:"restored"`
+	Skipped  []ImportRollbackSkip `json:"skipped"`
+}
+
+type ImportJobRepository struct{}
+
+func NewImportJobRepository() *ImportJobRepository {
+	return &ImportJobRepository{}
+}
+
+func (r *ImportJobRepository) Create(job *models.ImportJob) error {
+	return database.DB.Create(job).Error
+}
+
+// Update grava o estado atual do job (situação, totais e datas)
+func (r *ImportJobRepository) Update(job *models.ImportJob) error {
+	return database.DB.Save(job).Error
+}
+
+// GetAll lista os jobs, do mais recente para o mais antigo
+func (r *ImportJobRepository) GetAll() ([]models.ImportJob, error) {
+	var jobs []models.ImportJob
+	err := database.DB.Order("created_at DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *ImportJobRepository) GetByID(id string) (models.ImportJob, error) {
+	var job models.ImportJob
+	err := database.DB.First(&job, "id = ?", id).Error
+	return job, err
+}
+
+// Changes lista os clientes inseridos e atualizados pelo job, na ordem em que foram gravados
+func (r *ImportJobRepository) Changes(jobID string) ([]models.ImportJobChange, error) {
+	var changes []models.ImportJobChange
+	err := database.DB.Where("import_job_id = ?", jobID).Order("id").Find(&changes).Error
+	return changes, err
+}
+
+// Rollback desfaz um job concluído ou interrompido em uma única transação: clientes inseridos são excluídos e clientes
+// atualizados voltam aos dados anteriores, junto com os contatos criados pelo job. Clientes alterados depois do
+// job (por outra importação ou manualmente) são mantidos com seus contatos e listados em Skipped. As exclusões e restaurações entram no histórico dos clientes em nome de audit.
+func (r *ImportJobRepository) Rollback(job *models.ImportJob, audit Audit) (ImportRollbackResult, error) {
+	result := ImportRollbackResult{Skipped: []ImportRollbackSkip{}}
+	if !job.Rollbackable() {
+		return result, ErrImportJobNotRollbackable
+	}
+	audit.ImportJobID = job.ID
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var changes []models.ImportJobChange
+		if err := tx.Where("import_job_id = ?", job.ID).Order("id DESC").Find(&changes).Error; err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if err := lockClients(tx, change.ClientID); err != nil {
+				return err
+			}
+			var current models.Client
+			err := tx.First(&current, "id = ?", change.ClientID).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue // já excluído
+			}
+			if err != nil {
+				return err
+			}
+			later, err := laterRevision(tx, change.ClientID, job)
+			if err != nil {
+				return err
+			}
+			if reason := job.RollbackSkipReason(&current, later); reason != "" {
+				result.Skipped = append(result.Skipped, ImportRollbackSkip{ClientID: change.ClientID, Reason: reason})
+				continue
+			}
+			// Só os contatos criados pelo job nos clientes revertidos; os dos clientes mantidos continuam valendo
+			if err := tx.Where("import_job_id = ? AND client_id = ?", job.ID, change.ClientID).Delete(&models.ClientContact{}).Error; err != nil {
+				return err
+			}
+			switch {
+			case change.Action == models.ImportActionInserted:
+				if err := tx.Delete(&models.Client{}, "id = ?", change.ClientID).Error; err != nil {
+					return err
+				}
+				if err := recordRevision(tx, audit, models.RevisionDeleted, &current, nil); err != nil {
+					return err
+				}
+				result.Deleted++
+			case change.Previous != nil:
+				previous := *change.Previous
+				if err := tx.Save(&previous).Error; err != nil {
+					return err
+				}
+				if err := syncPrimaryContact(tx, &previous, ""); err != nil {
+					return err
+				}
+				if err := recordRevision(tx, audit, models.RevisionRestored, &current, &previous); err != nil {
+					return err
+				}
+				result.Restored++
+			}
+		}
+		now := time.Now()
+		job.Status, job.RolledBackAt = models.ImportJobRolledBack, &now
+		return tx.Save(job).Error
+	})
+	return result, clientConflict(database.DB, err)
+}
+
+// laterRevision busca a revisão mais recente do cliente feita fora do job e depois das gravações dele
+// (edição manual, mesclagem, restauração ou outra importação). Retorna nil se o cliente não mudou desde o job.
+func laterRevision(tx *gorm.DB, clientID string, job *models.ImportJob) (*models.ClientRevision, error) {
+	var revs []models.ClientRevision
+	err := tx.Where("client_id = ? AND import_job_id <> ? AND created_at >= ?", clientID, job.ID, job.CreatedAt).
+		Where("version > (SELECT COALESCE(MAX(version), 0) FROM client_revisions WHERE client_id = ? AND import_job_id = ?)", clientID, job.ID).
+		Order("version DESC").Limit(1).Find(&revs).Error
+	if err != nil || len(revs) == 0 {
+		return nil, err
+	}
+	return &revs[0], nil
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"minha-api/database"
+	"minha-api/models"
+)
+
+type ImportTemplateRepository struct{}
+
+func NewImportTemplateRepository() *ImportTemplateRepository {
+	return &ImportTemplateRepository{}
+}
+
+func (r *ImportTemplateRepository) Create(t *models.ImportTemplate) error {
+	return database.DB.Create(t).Error
+}
+
+func (r *ImportTemplateRepository) GetAll() ([]models.ImportTemplate, error) {
+	var templates []models.ImportTemplate
+	err := database.DB.Order("name").Find(&templates).Error
+	return templates, err
+}
+
+func (r *ImportTemplateRepository) GetByID(id string) (models.ImportTemplate, error) {
+	var t models.ImportTemplate
+	err := database.DB.First(&t, "id = ?", id).Error
+	return t, err
+}
+
+func (r *ImportTemplateRepository) Update(t *models.ImportTemplate) error {
+	return database.DB.Model(&models.ImportTemplate{}).Where("id = ?", t.ID).
+		Select("name", "description", "columns").Updates(t).Error
+}
+
+func (r *ImportTemplateRepository) Delete(id string) error {
+	return database.DB.Delete(&models.ImportTemplate{}, "id = ?", id).Error
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"minha-api/utils"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// ListResult é uma página de uma listagem
+type ListResult[T any] struct {
+	Items      []T
+	Total      int64  // itens que atendem aos filtros, sem paginação
+	NextCursor string // cursor da próxima página; vazio na última
+}
+
+// Campos liberados para filtro e ordenação em cada listagem (utils.ParseListQuery)
+var (
+	BookListSpec = utils.ListSpec{
+		Fields: map[string]utils.ListField{
+			"id":         {Column: "id", Type: utils.ListString},
+			"title":      {Column: "title", Type: utils.ListString},
+			"author":     {Column: "author", Type: utils.ListString},
+			"created_at": {Column: "created_at", Type: utils.ListTime},
+		},
+		DefaultSort: "-created_at", KeyField: "id", DefaultLimit: 50, MaxLimit: 500,
+	}
+	FileProcessListSpec = utils.ListSpec{
+		Fields: map[string]utils.ListField{
+			"id":          {Column: "id", Type: utils.ListString},
+			"fileName":    {Column: "file_name", Type: utils.ListString},
+			"status":      {Column: "status", Type: utils.ListString},
+			"received_at": {Column: "received_at", Type: utils.ListTime},
+		},
+		DefaultSort: "-received_at", KeyField: "id", DefaultLimit: 50, MaxLimit: 500,
+	}
+	ClientListSpec = utils.ListSpec{
+		Fields: map[string]utils.ListField{
+			"id":                   {Column: "id", Type: utils.ListString},
+			"name":                 {Column: "name", Type: utils.ListString},
+			"email":                {Column: "email", Type: utils.ListString},
+			"document":             {Column: "document", Type: utils.ListString},
+			"person_type":          {Column: "person_type", Type: utils.ListString},
+			"phone_type":           {Column: "phone_type", Type: utils.ListString},
+			"address_parts.street": {Column: "address_street", Type: utils.ListString},
+			"address_parts.city":   {Column: "address_city", Type: utils.ListString},
+			"address_parts.uf":     {Column: "address_uf", Type: utils.ListString},
+			"address_parts.cep":    {Column: "address_cep", Type: utils.ListString},
+		},
+		DefaultSort: "name", KeyField: "id", DefaultLimit: 50, MaxLimit: 500,
+	}
+)
+
+// applyListFilters acrescenta à consulta os filtros da listagem. As colunas vêm da ListSpec, nunca do usuário.
+func applyListFilters(db *gorm.DB, q utils.ListQuery) *gorm.DB {
+	for _, f := range q.Filters {
+		switch f.Op {
+		case utils.OpEq:
+			db = db.Where(f.Column+" = ?", f.Values[0])
+		case utils.OpILike:
+			db = db.Where(f.Column+" ILIKE ?", "%"+utils.EscapeLike(f.Values[0].(string))+"%")
+		case utils.OpIn:
+			db = db.Where(f.Column+" IN ?", f.Values)
+		case utils.OpGte:
+			db = db.Where(f.Column+" >= ?", f.Values[0])
+		case utils.OpLte:
+			db = db.Where(f.Column+" <= ?", f.Values[0])
+		}
+	}
+	return db
+}
+
+// listPage executa a listagem: conta o total, aplica ordenação e paginação (por offset ou por cursor)
+// e gera o cursor da próxima página. db já deve trazer os filtros próprios do endpoint, se houver.
+func listPage[T any](db *gorm.DB, q utils.ListQuery) (ListResult[T], error) {
+	var result ListResult[T]
+	db = applyListFilters(db.Model(new(T)), q)
+	if err := db.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return result, err
+	}
+
+	page := db.Session(&gorm.Session{})
+	if q.After != nil {
+		cond, args := q.KeysetCondition()
+		page = page.Where(cond, args...)
+	}
+	for _, s := range q.Sort {
+		if s.Desc {
+			page = page.Order(s.Expr() + " DESC")
+		} else {
+			page = page.Order(s.Expr())
+		}
+	}
+	// Busca um item a mais para saber se existe próxima página
+	if err := page.Offset(q.Offset).Limit(q.Limit + 1).Find(&result.Items).Error; err != nil {
+		return result, err
+	}
+	if len(result.Items) > q.Limit {
+		result.Items = result.Items[:q.Limit]
+		values, err := sortValues(db, q.Sort, &result.Items[q.Limit-1])
+		if err != nil {
+			return result, err
+		}
+		result.NextCursor = utils.EncodeListCursor(q.Sort, values)
+	}
+	return result, nil
+}
+
+// sortValues lê do item os valores das colunas de ordenação, usando o mapeamento do gorm
+func sortValues[T any](db *gorm.DB, sorts []utils.ListSort, item *T) ([]interface{}, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(item); err != nil {
+		return nil, err
+	}
+	rv := reflect.ValueOf(item).Elem()
+	values := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		field := stmt.Schema.LookUpField(s.Column)
+		if field == nil {
+			return nil, gorm.ErrInvalidField
+		}
+		values[i], _ = field.ValueOf(context.Background(), rv)
+	}
+	return values, nil
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"minha-api/database"
+	"minha-api/models"
+)
+
+type SegmentRepository struct{}
+
+func NewSegmentRepository() *SegmentRepository {
+	return &SegmentRepository{}
+}
+
+func (r *SegmentRepository) Create(s *models.Segment) error {
+	return database.DB.Create(s).Error
+}
+
+func (r *SegmentRepository) GetAll() ([]models.Segment, error) {
+	var segments []models.Segment
+	err := database.DB.Order("name").Find(&segments).Error
+	return segments, err
+}
+
+func (r *SegmentRepository) GetByID(id string) (models.Segment, error) {
+	var s models.Segment
+	err := database.DB.First(&s, "id = ?", id).Error
+	return s, err
+}
+
+// ExistsByName verifica se outro segmento (diferente de exceptID) já usa o nome
+func (r *SegmentRepository) ExistsByName(name, exceptID string) (bool, error) {
+	db := database.DB.Model(&models.Segment{}).Where("name = ?", name)
+	if exceptID != "" {
+		db = db.Where("id <> ?", exceptID)
+	}
+	var count int64
+	err := db.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *SegmentRepository) Update(s *models.Segment) error {
+	return database.DB.Model(&models.Segment{}).Where("id = ?", s.ID).
+		Select("name", "description", "filter").Updates(s).Error
+}
+
+func (r *SegmentRepository) Delete(id string) error {
+	return database.DB.Delete(&models.Segment{}, "id = ?", id).Error
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"minha-api/database"
+	"minha-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagWithCount é uma tag com a quantidade de clientes marcados com ela
+type TagWithCount struct {
+	models.Tag
+	Clients int64 `json:"clients"`
+}
+
+// ClientTagsResult resume uma marcação em lote
+type ClientTagsResult struct {
+	Added    int64    `json:"added"`     // associações cliente-tag criadas
+	Removed  int64    `json:"removed"`   // associações cliente-tag removidas
+	NotFound []string `json:"not_found"` // IDs de clientes inexistentes, ignorados
+}
+
+type TagRepository struct{}
+
+func NewTagRepository() *TagRepository {
+	return &TagRepository{}
+}
+
+// GetAll lista as tags em ordem alfabética, com a quantidade de clientes de cada uma
+func (r *TagRepository) GetAll() ([]TagWithCount, error) {
+	var tags []TagWithCount
+	err := database.DB.Model(&models.Tag{}).
+		Select("tags.*, (SELECT count(*) FROM client_tags JOIN clients ON clients.id = client_tags.client_id AND clients.deleted_at IS NULL WHERE client_tags.tag_id = tags.id) AS clients").
+		Order("lower(name)").
+		Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagRepository) GetByID(id string) (models.Tag, error) {
+	var t models.Tag
+	err := database.DB.First(&t, "id = ?", id).Error
+	return t, err
+}
+
+// FindByName busca a tag pelo nome, sem diferenciar maiúsculas
+func (r *TagRepository) FindByName(name string) (*models.Tag, error) {
+	var t models.Tag
+	err := database.DB.Where("lower(name) = lower(?)", name).First(&t).Error
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TagRepository) Create(t *models.Tag) error {
+	return database.DB.Create(t).Error
+}
+
+func (r *TagRepository) Update(t *models.Tag) error {
+	return database.DB.Model(&models.Tag{}).Where("id = ?", t.ID).Select("name").Updates(t).Error
+}
+
+// Delete exclui a tag e a desassocia de todos os clientes
+func (r *TagRepository) Delete(id string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM client_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, "id = ?", id).Error
+	})
+}
+
+// UpdateClientTags aplica, em uma transação, as tags add aos clientes e retira deles as tags remove.
+// Tags de add que ainda não existem são criadas; tags de remove inexistentes são ignoradas.
+// Os nomes já devem estar normalizados (models.NormalizeTagName).
+func (r *TagRepository) UpdateClientTags(clientIDs, add, remove []string) (ClientTagsResult, error) {
+	result := ClientTagsResult{NotFound: []string{}}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var found []string
+		if err := tx.Model(&models.Client{}).Where("id IN ?", clientIDs).Pluck("id", &found).Error; err != nil {
+			return err
+		}
+		known := map[string]bool{}
+		for _, id := range found {
+			known[strings.ToLower(id)] = true
+		}
+		for _, id := range clientIDs {
+			if !known[strings.ToLower(id)] {
+				result.NotFound = append(result.NotFound, id)
+			}
+		}
+		if len(found) == 0 {
+			return nil
+		}
+
+		if len(add) > 0 {
+			tags, err := findOrCreateTags(tx, add)
+			if err != nil {
+				return err
+			}
+			links := make([]map[string]interface{}, 0, len(found)*len(tags))
+			for _, clientID := range found {
+				for _, t := range tags {
+					links = append(links, map[string]interface{}{"client_id": clientID, "tag_id": t.ID})
+				}
+			}
+			res := tx.Table("client_tags").Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 500)
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Added = res.RowsAffected
+		}
+		if len(remove) > 0 {
+			res := tx.Exec(`DELETE FROM client_tags WHERE client_id IN ?
+				AND tag_id IN (SELECT id FROM tags WHERE lower(name) IN ?)`, found, lowerAll(remove))
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Removed = res.RowsAffected
+		}
+		return nil
+	})
+	return result, err
+}
+
+// findOrCreateTags retorna as tags com os nomes informados, criando as que faltam
+func findOrCreateTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	if err := tx.Where("lower(name) IN ?", lowerAll(names)).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, t := range tags {
+		existing[strings.ToLower(t.Name)] = true
+	}
+	for _, name := range names {
+		if existing[strings.ToLower(name)] {
+			continue
+		}
+		t := models.Tag{Name: name}
+		if err := tx.Create(&t).Error; err != nil {
+			return nil, err
+		}
+		existing[strings.ToLower(name)] = true
+		tags = append(tags, t)
+	}
+	return tags, nil
+}
+
+// tagsByClientIDs busca de uma vez as tags de vários clientes, indexadas pelo ID do cliente
+func tagsByClientIDs(db *gorm.DB, clientIDs []string) (map[string][]models.Tag, error) {
+	byClient := map[string][]models.Tag{}
+	if len(clientIDs) == 0 {
+		return byClient, nil
+	}
+	var rows []struct {
+		ClientID string
+		models.Tag
+	}
+	err := db.Table("client_tags").
+		Select("client_tags.client_id, tags.*").
+		Joins("JOIN tags ON tags.id = client_tags.tag_id").
+		Where("client_tags.client_id IN ?", clientIDs).
+		Order("lower(tags.name)").
+		Scan(&rows).Error
+	for _, row := range rows {
+		byClient[row.ClientID] = append(byClient[row.ClientID], row.Tag)
+	}
+	return byClient, err
+}
+
+func lowerAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
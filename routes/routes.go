@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"context"
 	"minha-api/controllers"
 	middlewares "minha-api/middleware"
 	"minha-api/repositories"
@@ -11,6 +12,7 @@ import (
 
 func SetupRoutes() *gin.Engine {
 	r := gin.Default()
+	r.Use(middlewares.RequestContextMiddleware())
 
 	repo := repositories.NewBookRepository()
 	controller := controllers.NewBookController(repo)
@@ -19,10 +21,26 @@ func SetupRoutes() *gin.Engine {
 	fileController := controllers.NewFileProcessController(fileRepo, &utils.RealS3Uploader{}, &utils.RealS3Presigner{})
 
 	clientRepo := repositories.NewClientRepository()
-	clientController := controllers.NewClientController(clientRepo)
+	templateRepo := repositories.NewImportTemplateRepository()
+	importJobRepo := repositories.NewImportJobRepository()
+	companyRegistry := utils.NewCompanyRegistryFromEnv()
+	clientController := controllers.NewClientController(clientRepo, templateRepo, importJobRepo, companyRegistry)
+	templateController := controllers.NewImportTemplateController(templateRepo)
+	importJobController := controllers.NewImportJobController(importJobRepo)
 
 	clientCRUDController := controllers.NewClientCRUDController(clientRepo)
 	clientExportController := controllers.NewClientExportController(clientRepo, &utils.RealS3Uploader{}, &utils.RealS3Presigner{})
+	clientDuplicateController := controllers.NewClientDuplicateController(clientRepo)
+	clientContactController := controllers.NewClientContactController(clientRepo)
+	clientHistoryController := controllers.NewClientHistoryController(clientRepo)
+	clientEnrichController := controllers.NewClientEnrichController(clientRepo, companyRegistry)
+	tagController := controllers.NewTagController(repositories.NewTagRepository())
+	segmentController := controllers.NewSegmentController(repositories.NewSegmentRepository(), clientRepo)
+
+	exportJobRepo := repositories.NewExportJobRepository()
+	exportWorker := controllers.NewExportWorker(exportJobRepo, clientRepo, &utils.RealS3Uploader{}, &utils.RealS3Deleter{})
+	exportWorker.Start(context.Background())
+	exportJobController := controllers.NewExportJobController(exportJobRepo, exportWorker, &utils.RealS3Presigner{})
 
 	books := r.Group("/books", middlewares.ApiKeyMiddleware())
 	{
@@ -43,14 +61,56 @@ func SetupRoutes() *gin.Engine {
 		files.GET(":id/download", fileController.DownloadFile) // nova rota de download
 	}
 
-	r.POST("/clients/upload", clientController.UploadClients) // novo endpoint para upload de clientes
-
-	r.GET("/clients", clientCRUDController.GetAll)
-	r.GET("/clients/:id", clientCRUDController.GetByID)
-	r.POST("/clients", clientCRUDController.Create)
-	r.PUT("/clients/:id", clientCRUDController.Update)
-	r.DELETE("/clients/:id", clientCRUDController.Delete)
-	r.GET("/clients/export", clientExportController.ExportClients) // nova rota para exportação de clientes
+	// Clientes, importações, exportações e cadastros auxiliares alteram dados e registram o autor no histórico:
+	// exigem a API key, cujo usuário é o autor
+	api := r.Group("", middlewares.ApiKeyMiddleware())
+
+	api.POST("/clients/upload", clientController.UploadClients) // novo endpoint para upload de clientes
+
+	api.GET("/clients", clientCRUDController.GetAll)
+	api.GET("/clients/search", clientCRUDController.Search)
+	api.GET("/clients/:id", clientCRUDController.GetByID)
+	api.POST("/clients", clientCRUDController.Create)
+	api.PUT("/clients/:id", clientCRUDController.Update)
+	api.PATCH("/clients/:id", clientCRUDController.Patch)
+	api.DELETE("/clients/:id", clientCRUDController.Delete)
+	api.GET("/clients/export", clientExportController.ExportClients) // nova rota para exportação de clientes
+	api.GET("/clients/duplicates", clientDuplicateController.Duplicates)
+	api.POST("/clients/merge", clientDuplicateController.Merge)
+	api.POST("/clients/tags", tagController.UpdateClients)
+	api.GET("/clients/:id/contacts", clientContactController.GetAll)
+	api.POST("/clients/:id/contacts", clientContactController.Create)
+	api.GET("/clients/:id/contacts/:contactId", clientContactController.GetByID)
+	api.PUT("/clients/:id/contacts/:contactId", clientContactController.Update)
+	api.DELETE("/clients/:id/contacts/:contactId", clientContactController.Delete)
+	api.GET("/clients/:id/history", clientHistoryController.History)
+	api.POST("/clients/:id/history/:version/restore", clientHistoryController.Restore)
+	api.POST("/clients/:id/enrich", clientEnrichController.Enrich)
+
+	api.GET("/import-templates", templateController.GetAll)
+	api.GET("/import-templates/:id", templateController.GetByID)
+	api.POST("/import-templates", templateController.Create)
+	api.PUT("/import-templates/:id", templateController.Update)
+	api.DELETE("/import-templates/:id", templateController.Delete)
+
+	api.GET("/tags", tagController.GetAll)
+	api.POST("/tags", tagController.Create)
+	api.PUT("/tags/:id", tagController.Update)
+	api.DELETE("/tags/:id", tagController.Delete)
+
+	api.GET("/segments", segmentController.GetAll)
+	api.GET("/segments/:id", segmentController.GetByID)
+	api.GET("/segments/:id/clients", segmentController.Clients)
+	api.POST("/segments", segmentController.Create)
+	api.PUT("/segments/:id", segmentController.Update)
+	api.DELETE("/segments/:id", segmentController.Delete)
+
+	api.GET("/imports", importJobController.GetAll)
+	api.GET("/imports/:id", importJobController.GetByID)
+	api.POST("/imports/:id/rollback", importJobController.Rollback)
+
+	api.POST("/exports", exportJobController.Create)
+	api.GET("/exports/:id", exportJobController.GetByID)
 
 	return r
 }
@@ -58,6 +118,7 @@ func SetupRoutes() *gin.Engine {
 // Ajuste: Remove interfaces indefinidas e usa tipos concretos dos mocks
 func SetupRoutesWithReposAndS3(bookRepo repositories.BookRepositoryInterface, fileRepo repositories.FileProcessRepositoryInterface, s3uploader utils.S3Uploader, s3presigner utils.S3Presigner) *gin.Engine {
 	r := gin.Default()
+	r.Use(middlewares.RequestContextMiddleware())
 
 	controller := controllers.NewBookController(bookRepo)
 	fileController := controllers.NewFileProcessController(fileRepo, s3uploader, s3presigner)
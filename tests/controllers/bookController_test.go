@@ -1,6 +1,7 @@
 package controllers_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -78,3 +79,47 @@ func TestBooksMethodNotAllowed(t *testing.T) {
 		t.Errorf("Esperado 405 ou 404 para método não permitido, obteve %d", w.Code)
 	}
 }
+
+func TestCreateBookValidationErrors(t *testing.T) {
+	r := setupRouter()
+	req, _ := http.NewRequest("POST", "/books", strings.NewReader(`{"title":"","author":"Autor"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "minha-chave-secreta")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Esperado status 400, obteve %d", w.Code)
+	}
+	var resp struct {
+		Errors []struct {
+			Field string `json:"field"`
+			Code  string `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Resposta não é JSON: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "title" || resp.Errors[0].Code != "required" {
+		t.Errorf("Esperado erro required em title, obteve %+v", resp.Errors)
+	}
+}
+
+func TestUpdateBookValidationErrors(t *testing.T) {
+	r := setupRouter()
+	req, _ := http.NewRequest("PUT", "/books/00000000-0000-0000-0000-000000000000", strings.NewReader(`{"title":"","author":"Autor"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "minha-chave-secreta")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Esperado status 400, obteve %d", w.Code)
+	}
+	var resp struct {
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Errors) != 1 || resp.Errors[0].Field != "title" {
+		t.Errorf("Esperado erro de validação em title, obteve %s", w.Body.String())
+	}
+}
@@ -0,0 +1,34 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+)
+
+func TestUpdateClientPeloAliasDaMesclagem(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	repo := repositories.NewClientRepository()
+	mantido := models.Client{Name: "Mantido", Document: testutils.RandomCNPJ()}
+	removido := models.Client{Name: "Removido", Document: testutils.RandomCNPJ()}
+	for _, c := range []*models.Client{&mantido, &removido} {
+		if err := repo.Create(c); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if err := repo.Merge(&mantido, removido.ID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	w := enviarJSON(router, "PUT", "/clients/"+removido.ID, map[string]string{"name": "Atualizado Pelo Alias", "document": mantido.Document})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Esperado status 200, obteve %d: %s", w.Code, w.Body.String())
+	}
+	if body := lerJSON(t, w); body["id"] != mantido.ID || body["name"] != "Atualizado Pelo Alias" {
+		t.Errorf("PUT pelo alias deveria atualizar o cliente mesclado: %v", body)
+	}
+}
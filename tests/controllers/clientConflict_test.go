@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"minha-api/tests/testutils"
+)
+
+func TestCreateClientConflict(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	documento, email := testutils.RandomCNPJ(), testutils.RandomEmail("conflito")
+	w := enviarJSON(router, "POST", "/clients", map[string]string{"name": "Original", "document": documento, "email": email})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+	original := lerJSON(t, w)
+
+	casos := []struct {
+		nome  string
+		body  map[string]string
+		campo string
+	}{
+		{"mesmo documento", map[string]string{"name": "Cópia", "document": documento}, "document"},
+		{"mesmo email com outra caixa", map[string]string{"name": "Cópia", "document": testutils.RandomCNPJ(), "email": strings.ToUpper(email)}, "email"},
+	}
+	for _, c := range casos {
+		w := enviarJSON(router, "POST", "/clients", c.body)
+		if w.Code != http.StatusConflict {
+			t.Errorf("%s: esperado status 409, obteve %d: %s", c.nome, w.Code, w.Body.String())
+			continue
+		}
+		body := lerJSON(t, w)
+		if body["field"] != c.campo || body["client_id"] != original["id"] || body["error"] == "" {
+			t.Errorf("%s: resposta 409 inesperada: %v", c.nome, body)
+		}
+	}
+
+	// Violação de outro índice único (chave primária repetida) não é conflito de cliente: continua 500
+	w = enviarJSON(router, "POST", "/clients", map[string]interface{}{"id": original["id"], "name": "Mesmo ID", "document": testutils.RandomCNPJ()})
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("ID repetido: esperado status 500, obteve %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestImportClientConflict(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	email := testutils.RandomEmail("importacao")
+	if w := enviarJSON(router, "POST", "/clients", map[string]string{"name": "Dono do Email", "document": testutils.RandomCNPJ(), "email": email}); w.Code != http.StatusCreated {
+		t.Fatalf("Esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+
+	// A primeira linha usa o email de outro cliente: o lote falha, é gravado linha a linha e só ela é ignorada
+	csv := fmt.Sprintf("nome;documento;email\nOutro Nome;%s;%s\nCliente Novo;%s;%s\n",
+		testutils.RandomCNPJ(), email, testutils.RandomCNPJ(), testutils.RandomEmail("novo"))
+	w := enviarPlanilha(t, router, "", "clientes.csv", []byte(csv))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+	body := lerJSON(t, w)
+	if body["clientes importados"] != 1.0 || body["ignorados por duplicidade"] != 1.0 || body["erros de banco"] != 0.0 {
+		t.Errorf("totais inesperados: %v", body)
+	}
+}
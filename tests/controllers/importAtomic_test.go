@@ -0,0 +1,85 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+)
+
+func TestUploadAtomicInvalido(t *testing.T) {
+	router := testutils.SetupClientRouter()
+	w := enviarPlanilha(t, router, "atomic=talvez", "clientes.csv", []byte("nome\nACME\n"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Esperado status 400, obteve %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// planilhaComLinhaInvalida tem um cliente válido e uma linha com CPF/CNPJ inválido
+func planilhaComLinhaInvalida() (string, []byte) {
+	documento := testutils.RandomCNPJ()
+	csv := fmt.Sprintf("nome;documento\nCliente Válido;%s\nCliente Inválido;123\n", documento)
+	return documento, []byte(csv)
+}
+
+func TestUploadAtomicDesfazTudo(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	documento, csv := planilhaComLinhaInvalida()
+
+	w := enviarPlanilha(t, router, "atomic=true", "clientes.csv", csv)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Esperado status 400, obteve %d: %s", w.Code, w.Body.String())
+	}
+	body := lerJSON(t, w)
+	if erros, _ := body["errors"].([]interface{}); len(erros) != 1 || body["import_job_id"] == "" {
+		t.Errorf("resposta deveria listar a linha inválida e o job: %v", body)
+	}
+	if c, _ := repositories.NewClientRepository().FindByDocument(documento); c != nil {
+		t.Error("importação atômica cancelada gravou o cliente válido")
+	}
+	job, err := repositories.NewImportJobRepository().GetByID(fmt.Sprint(body["import_job_id"]))
+	if err != nil || job.Status != models.ImportJobCancelled {
+		t.Errorf("job deveria ficar cancelado: %+v, %v", job, err)
+	}
+}
+
+func TestUploadAtomicSavepoint(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	documento, csv := planilhaComLinhaInvalida()
+
+	w := enviarPlanilha(t, router, "atomic=savepoint", "clientes.csv", csv)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+	body := lerJSON(t, w)
+	if body["clientes importados"] != 1.0 || body["invalidos"] != 1.0 || body["atomic"] != "savepoint" {
+		t.Errorf("totais inesperados: %v", body)
+	}
+	if c, _ := repositories.NewClientRepository().FindByDocument(documento); c == nil {
+		t.Error("com savepoint a linha válida deveria ser gravada")
+	}
+}
+
+func TestUploadInterrompidoPeloLimite(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	csv := fmt.Sprintf("nome;documento\nPrimeiro;%s\nSegundo;%s\n", testutils.RandomCNPJ(), testutils.RandomCNPJ())
+
+	w := enviarPlanilha(t, router, "maxRows=1", "clientes.csv", []byte(csv))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Esperado status 400, obteve %d: %s", w.Code, w.Body.String())
+	}
+	body := lerJSON(t, w)
+	job, err := repositories.NewImportJobRepository().GetByID(fmt.Sprint(body["import_job_id"]))
+	if err != nil || job.Status != models.ImportJobInterrupted {
+		t.Fatalf("job deveria ficar interrompido: %+v, %v", job, err)
+	}
+	if _, err := repositories.NewImportJobRepository().Rollback(&job, repositories.Audit{Actor: "teste"}); err != nil {
+		t.Errorf("importação interrompida deveria poder ser desfeita: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+)
+
+func TestUploadPreviewTokenInvalido(t *testing.T) {
+	router := testutils.SetupClientRouter()
+	w := enviarPlanilha(t, router, "previewToken=nao-existe", "clientes.csv", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Esperado status 400, obteve %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUploadDryRunEPreviewToken(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	repo := repositories.NewClientRepository()
+	documento := testutils.RandomCNPJ()
+	csv := fmt.Sprintf("nome;documento;email\nCliente Prévia;%s;%s\n", documento, testutils.RandomEmail("previa"))
+
+	inicio := time.Now()
+	w := enviarPlanilha(t, router, "dryRun=true", "clientes.csv", []byte(csv))
+	if w.Code != http.StatusOK {
+		t.Fatalf("dry-run: esperado status 200, obteve %d: %s", w.Code, w.Body.String())
+	}
+	body := lerJSON(t, w)
+	token, _ := body["preview_token"].(string)
+	if token == "" {
+		t.Fatalf("dry-run sem preview_token: %v", body)
+	}
+	expira, err := time.Parse(time.RFC3339Nano, fmt.Sprint(body["expires_at"]))
+	if err != nil {
+		t.Fatalf("expires_at inválido: %v", body["expires_at"])
+	}
+	if ttl := expira.Sub(inicio); ttl < 30*time.Minute-time.Second || ttl > 30*time.Minute+time.Second {
+		t.Errorf("validade da pré-visualização: esperado 30 minutos, obteve %s", ttl)
+	}
+	if c, _ := repo.FindByDocument(documento); c != nil {
+		t.Fatal("dry-run gravou o cliente")
+	}
+
+	// Efetiva sem reenviar o arquivo
+	w = enviarPlanilha(t, router, "previewToken="+token, "clientes.csv", nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("efetivação: esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+	if c, _ := repo.FindByDocument(documento); c == nil || c.Name != "Cliente Prévia" {
+		t.Errorf("cliente da pré-visualização não gravado: %+v", c)
+	}
+
+	// O token só vale uma vez
+	if w := enviarPlanilha(t, router, "previewToken="+token, "clientes.csv", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("token reutilizado: esperado status 400, obteve %d", w.Code)
+	}
+}
+
+func TestUploadPreviewTokenExpirado(t *testing.T) {
+	testutils.TestDatabase(t)
+	t.Setenv("IMPORT_PREVIEW_TTL", "50ms")
+	router := testutils.SetupClientRouter()
+	csv := fmt.Sprintf("nome;documento\nCliente Expirado;%s\n", testutils.RandomCNPJ())
+
+	w := enviarPlanilha(t, router, "dryRun=true", "clientes.csv", []byte(csv))
+	if w.Code != http.StatusOK {
+		t.Fatalf("dry-run: esperado status 200, obteve %d: %s", w.Code, w.Body.String())
+	}
+	token, _ := lerJSON(t, w)["preview_token"].(string)
+	time.Sleep(100 * time.Millisecond)
+	if w := enviarPlanilha(t, router, "previewToken="+token, "clientes.csv", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("token expirado: esperado status 400, obteve %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUploadDryRunAcimaDoLimiteDeLinhas(t *testing.T) {
+	testutils.TestDatabase(t)
+	t.Setenv("IMPORT_PREVIEW_MAX_ROWS", "1")
+	router := testutils.SetupClientRouter()
+	csv := fmt.Sprintf("nome;documento\nPrimeiro;%s\nSegundo;%s\n", testutils.RandomCNPJ(), testutils.RandomCNPJ())
+
+	w := enviarPlanilha(t, router, "dryRun=true", "clientes.csv", []byte(csv))
+	if w.Code != http.StatusOK {
+		t.Fatalf("dry-run: esperado status 200, obteve %d: %s", w.Code, w.Body.String())
+	}
+	body := lerJSON(t, w)
+	if _, ok := body["preview_token"]; ok || body["warning"] == nil {
+		t.Errorf("arquivo acima do limite não deveria receber preview_token: %v", body)
+	}
+	if linhas, _ := body["rows"].([]interface{}); len(linhas) != 2 {
+		t.Errorf("dry-run deveria mostrar as 2 linhas, mostrou %d", len(linhas))
+	}
+}
+
+func TestUploadPreviewDescartaAMaisAntiga(t *testing.T) {
+	testutils.TestDatabase(t)
+	t.Setenv("IMPORT_PREVIEW_MAX", "1")
+	router := testutils.SetupClientRouter()
+
+	tokens := make([]string, 2)
+	for i := range tokens {
+		csv := fmt.Sprintf("nome;documento\nCliente %d;%s\n", i, testutils.RandomCNPJ())
+		w := enviarPlanilha(t, router, "dryRun=true", "clientes.csv", []byte(csv))
+		if w.Code != http.StatusOK {
+			t.Fatalf("dry-run: esperado status 200, obteve %d: %s", w.Code, w.Body.String())
+		}
+		tokens[i], _ = lerJSON(t, w)["preview_token"].(string)
+	}
+	if w := enviarPlanilha(t, router, "previewToken="+tokens[0], "clientes.csv", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("pré-visualização descartada: esperado status 400, obteve %d", w.Code)
+	}
+	if w := enviarPlanilha(t, router, "previewToken="+tokens[1], "clientes.csv", nil); w.Code != http.StatusCreated {
+		t.Errorf("pré-visualização mais recente: esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+}
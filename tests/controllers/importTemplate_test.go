@@ -0,0 +1,75 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+)
+
+func TestCreateImportTemplateInvalido(t *testing.T) {
+	router := testutils.SetupClientRouter()
+	casos := map[string]map[string]interface{}{
+		"sem nome":          {"columns": map[string]string{"Razão": "name"}},
+		"sem colunas":       {"name": "ERP"},
+		"campo inexistente": {"name": "ERP", "columns": map[string]string{"Razão": "razao_social"}},
+	}
+	for nome, body := range casos {
+		w := enviarJSON(router, "POST", "/import-templates", body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: esperado status 400, obteve %d: %s", nome, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestUploadComTemplateInvalido(t *testing.T) {
+	router := testutils.SetupClientRouter()
+	w := enviarPlanilha(t, router, "templateId=nao-e-uuid", "clientes.csv", []byte("nome\nACME\n"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Esperado status 400, obteve %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUploadComTemplate(t *testing.T) {
+	testutils.TestDatabase(t)
+	router := testutils.SetupClientRouter()
+	w := enviarJSON(router, "POST", "/import-templates", map[string]interface{}{
+		"name": "ERP Fornecedores",
+		// "Contato" seria o nome do contato pelos sinônimos embutidos; o template tem prioridade
+		"columns": map[string]string{"Razão do Fornecedor": "name", "Identificação": "document", "Contato": "email"},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+	template := lerJSON(t, w)
+	if w := enviarJSON(router, "GET", fmt.Sprintf("/import-templates/%s", template["id"]), nil); w.Code != http.StatusOK {
+		t.Fatalf("GET do template: esperado status 200, obteve %d", w.Code)
+	}
+
+	documento, email := testutils.RandomCNPJ(), testutils.RandomEmail("template")
+	csv := fmt.Sprintf("Razão do Fornecedor;Identificação;Contato;Telefone\nFornecedor Template;%s;%s;(11) 98765-4321\n", documento, email)
+	w = enviarPlanilha(t, router, fmt.Sprintf("templateId=%s", template["id"]), "fornecedores.csv", []byte(csv))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Esperado status 201, obteve %d: %s", w.Code, w.Body.String())
+	}
+	if body := lerJSON(t, w); body["clientes importados"] != 1.0 {
+		t.Fatalf("esperado 1 cliente importado, obteve %v", body)
+	}
+
+	client, err := repositories.NewClientRepository().FindByDocument(documento)
+	if err != nil || client == nil {
+		t.Fatalf("cliente importado não encontrado: %v", err)
+	}
+	// Colunas do template e, fora dele, os sinônimos embutidos (Telefone)
+	if client.Name != "Fornecedor Template" || client.Email != email || client.PhoneE164 != "+5511987654321" {
+		t.Errorf("colunas mapeadas incorretamente: %+v", client)
+	}
+
+	// Template inexistente
+	w = enviarPlanilha(t, router, "templateId=00000000-0000-4000-8000-000000000000", "fornecedores.csv", []byte(csv))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("template inexistente: esperado status 400, obteve %d", w.Code)
+	}
+}
@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"minha-api/controllers"
+	"minha-api/models"
+	"minha-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+)
+
+func routerLivros(qtd int) (*gin.Engine, *mocks.BookRepositoryMock) {
+	repo := &mocks.BookRepositoryMock{Books: map[string]models.Book{}}
+	for i := 1; i <= qtd; i++ {
+		id := fmt.Sprintf("%02d", i)
+		repo.Books[id] = models.Book{ID: id, Title: "Livro " + id}
+	}
+	router := gin.New()
+	router.GET("/books", controllers.NewBookController(repo).GetBooks)
+	return router, repo
+}
+
+func TestGetBooksEnvelope(t *testing.T) {
+	router, repo := routerLivros(5)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/books?title[ilike]=livro&sort=title&limit=2&offset=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data   []models.Book `json:"data"`
+		Total  int64         `json:"total"`
+		Limit  int           `json:"limit"`
+		Offset int           `json:"offset"`
+		Links  struct {
+			Self, Next, Prev string
+		} `json:"links"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != 2 || resp.Data[0].ID != "03" || resp.Total != 5 || resp.Limit != 2 || resp.Offset != 2 {
+		t.Errorf("envelope inesperado: %+v", resp)
+	}
+	if resp.Links.Next != "/books?limit=2&offset=4&sort=title&title%5Bilike%5D=livro" {
+		t.Errorf("links.next = %q", resp.Links.Next)
+	}
+	if resp.Links.Prev != "/books?limit=2&offset=0&sort=title&title%5Bilike%5D=livro" {
+		t.Errorf("links.prev = %q", resp.Links.Prev)
+	}
+	if f := repo.LastListQuery.Filters; len(f) != 1 || f[0].Column != "title" || f[0].Op != "ilike" {
+		t.Errorf("filtros repassados ao repositório: %+v", f)
+	}
+}
+
+func TestGetBooksFiltroInvalido(t *testing.T) {
+	router, _ := routerLivros(1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/books?deleted_at[gte]=2024-01-01", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("esperado 400 para campo fora da lista branca, obteve %d", w.Code)
+	}
+}
@@ -1,10 +1,18 @@
 package controllers_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
 	"minha-api/controllers"
+	middlewares "minha-api/middleware"
 	"minha-api/models"
 	"minha-api/tests/mocks"
 	"minha-api/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,3 +34,44 @@ func init() {
 	r.POST("/files/sendFiles", fileController.Create)
 	// ...adicione outras rotas conforme necessário...
 }
+
+// enviarJSON faz a requisição com o corpo em JSON e retorna a resposta
+func enviarJSON(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, path, bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", middlewares.ApiKeyEsperada)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// enviarPlanilha envia conteudo como o arquivo nomeArquivo para POST /clients/upload?query
+func enviarPlanilha(t *testing.T, router *gin.Engine, query, nomeArquivo string, conteudo []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	fileWriter, err := w.CreateFormFile("file", nomeArquivo)
+	if err != nil {
+		t.Fatalf("Erro ao criar form file: %v", err)
+	}
+	io.Copy(fileWriter, bytes.NewReader(conteudo))
+	w.Close()
+
+	req, _ := http.NewRequest("POST", "/clients/upload?"+query, &b)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("X-API-Key", middlewares.ApiKeyEsperada)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// lerJSON decodifica a resposta em um mapa
+func lerJSON(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("resposta não é JSON (%d): %s", w.Code, w.Body.String())
+	}
+	return body
+}
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"minha-api/models"
 	"minha-api/repositories"
+	"minha-api/utils"
+	"sort"
 	"time"
 )
 
 type BookRepositoryMock struct {
-	Books map[string]models.Book
+	Books         map[string]models.Book
+	LastListQuery utils.ListQuery // última consulta recebida por List
 }
 
 // Garante que BookRepositoryMock implementa BookRepositoryInterface
@@ -30,6 +33,18 @@ func (m *BookRepositoryMock) GetAll() ([]models.Book, error) {
 	return books, nil
 }
 
+// List ignora filtros e ordenação (registrados em LastListQuery) e pagina por offset, em ordem de ID
+func (m *BookRepositoryMock) List(q utils.ListQuery) (repositories.ListResult[models.Book], error) {
+	m.LastListQuery = q
+	items, _ := m.GetAll()
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	result := repositories.ListResult[models.Book]{Total: int64(len(items))}
+	start := min(q.Offset, len(items))
+	end := min(start+q.Limit, len(items))
+	result.Items = items[start:end]
+	return result, nil
+}
+
 func (m *BookRepositoryMock) GetByID(id string) (*models.Book, error) {
 	if b, ok := m.Books[id]; ok {
 		return &b, nil
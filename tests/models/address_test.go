@@ -0,0 +1,53 @@
+package models_test
+
+import (
+	"testing"
+
+	"minha-api/models"
+)
+
+func TestParseAddress(t *testing.T) {
+	casos := map[string]models.Address{
+		"Rua das Flores, 123, Apto 45 - Centro, São Paulo - SP, 01234-567": {
+			Street: "Rua das Flores", Number: "123", Complement: "Apto 45", Neighborhood: "Centro", City: "São Paulo", UF: "SP", CEP: "01234567",
+		},
+		"Av. Paulista, 1000 - Bela Vista - São Paulo/SP - CEP 01310-100": {
+			Street: "Av. Paulista", Number: "1000", Neighborhood: "Bela Vista", City: "São Paulo", UF: "SP", CEP: "01310100",
+		},
+		"Rua Sem Saída, s/n, Vila Nova, Curitiba, PR": {
+			Street: "Rua Sem Saída", Number: "s/n", Neighborhood: "Vila Nova", City: "Curitiba", UF: "PR",
+		},
+		"Rua X 123": {Street: "Rua X", Number: "123"},
+	}
+	for entrada, esperado := range casos {
+		if got := models.ParseAddress(entrada); got != esperado {
+			t.Errorf("ParseAddress(%q):\n esperado %+v\n obteve   %+v", entrada, esperado, got)
+		}
+	}
+}
+
+func TestAddressStringIdaEVolta(t *testing.T) {
+	a := models.Address{Street: "Rua 25 de Março", Number: "500", Neighborhood: "Centro", City: "São Paulo", UF: "SP", CEP: "01021200"}
+	linha := a.String()
+	if linha != "Rua 25 de Março, 500 - Centro, São Paulo - SP, 01021-200" {
+		t.Errorf("String(): obteve %q", linha)
+	}
+	if got := models.ParseAddress(linha); got != a {
+		t.Errorf("ParseAddress(String()): esperado %+v, obteve %+v", a, got)
+	}
+}
+
+func TestClientValidateEndereco(t *testing.T) {
+	c := models.Client{Name: "ACME", AddressParts: models.Address{Street: "Rua A", City: "Recife", UF: "XX", CEP: "123"}}
+	errs := c.Validate()
+	campos := map[string]bool{}
+	for _, e := range errs {
+		campos[e.Field] = true
+	}
+	if !campos["address_parts.cep"] || !campos["address_parts.uf"] {
+		t.Errorf("esperado erros em CEP e UF, obteve %v", errs)
+	}
+	if c.Address != "Rua A, Recife - XX, 123" {
+		t.Errorf("endereço combinado não foi remontado a partir das partes: %q", c.Address)
+	}
+}
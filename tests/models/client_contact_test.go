@@ -0,0 +1,112 @@
+package models_test
+
+import (
+	"testing"
+
+	"minha-api/models"
+)
+
+func TestNormalizeContactType(t *testing.T) {
+	casos := map[string]string{
+		"":           models.ContactOther,
+		"billing":    models.ContactBilling,
+		"Financeiro": models.ContactBilling,
+		"Cobrança":   models.ContactBilling,
+		"COMERCIAL":  models.ContactCommercial,
+		"Técnico":    models.ContactTechnical,
+		" outro ":    models.ContactOther,
+	}
+	for entrada, esperado := range casos {
+		if got, ok := models.NormalizeContactType(entrada); !ok || got != esperado {
+			t.Errorf("NormalizeContactType(%q) = %q, %v; esperado %q", entrada, got, ok, esperado)
+		}
+	}
+	if _, ok := models.NormalizeContactType("diretoria"); ok {
+		t.Error("tipo desconhecido deveria ser rejeitado")
+	}
+}
+
+func TestClientContactValidate(t *testing.T) {
+	ct := models.ClientContact{Type: "Financeiro", Name: "Ana", Phone: "(11) 98765-4321"}
+	if errs := ct.Validate(); errs != nil {
+		t.Fatalf("contato válido retornou erros: %v", errs)
+	}
+	if ct.Type != models.ContactBilling || ct.PhoneE164 != "+5511987654321" {
+		t.Errorf("normalização inesperada: tipo %q, telefone %q", ct.Type, ct.PhoneE164)
+	}
+
+	casos := []struct {
+		campo string
+		ct    models.ClientContact
+	}{
+		{"email", models.ClientContact{Name: "Sem canal"}},
+		{"type", models.ClientContact{Type: "diretoria", Email: "a@b.com"}},
+		{"phone", models.ClientContact{Phone: "abc"}},
+		{"email", models.ClientContact{Email: "invalido"}},
+	}
+	for _, c := range casos {
+		errs := c.ct.Validate()
+		if errs == nil || errs[0].Field != c.campo {
+			t.Errorf("esperado erro no campo %q, obteve %v", c.campo, errs)
+		}
+	}
+}
+
+func TestClientContactSameAs(t *testing.T) {
+	a := models.ClientContact{Type: models.ContactBilling, Name: "Ana", Email: "Ana@Empresa.com", Phone: "(11) 98765-4321"}
+	b := models.ClientContact{Type: models.ContactBilling, Name: "ana", Email: "ana@empresa.com", Phone: "11987654321"}
+	a.Validate()
+	b.Validate()
+	if !a.SameAs(&b) {
+		t.Error("contatos iguais a menos de maiúsculas e formatação deveriam ser o mesmo")
+	}
+	b.Type = models.ContactTechnical
+	if a.SameAs(&b) {
+		t.Error("contatos de tipos diferentes não são o mesmo")
+	}
+}
+
+func TestClientContactApplyTo(t *testing.T) {
+	client := models.Client{Email: "antigo@x.com", Phone: "1133334444"}
+	ct := models.ClientContact{Email: "novo@x.com", Phone: "(21) 98888-7777"}
+	ct.ApplyTo(&client)
+	if client.Email != "novo@x.com" || client.PhoneE164 != "+5521988887777" || client.PhoneType != "mobile" {
+		t.Errorf("cliente não recebeu o contato principal: %+v", client)
+	}
+}
+
+func TestClientContactApplyToKeepsEmptyFields(t *testing.T) {
+	client := models.Client{Email: "cliente@x.com", Phone: "1133334444"}
+	client.NormalizePhone()
+	ct := models.ClientContact{Phone: "(21) 98888-7777"}
+	ct.ApplyTo(&client)
+	if client.Email != "cliente@x.com" {
+		t.Errorf("contato só com telefone apagou o email do cliente: %q", client.Email)
+	}
+	if client.PhoneE164 != "+5521988887777" {
+		t.Errorf("telefone do contato não aplicado: %q", client.PhoneE164)
+	}
+
+	client = models.Client{Email: "cliente@x.com", Phone: "1133334444"}
+	client.NormalizePhone()
+	ct = models.ClientContact{Email: "contato@x.com"}
+	ct.ApplyTo(&client)
+	if client.Email != "contato@x.com" || client.Phone != "1133334444" || client.PhoneE164 != "+551133334444" {
+		t.Errorf("contato só com email alterou o telefone do cliente: %+v", client)
+	}
+}
+
+func TestClientContactSyncFrom(t *testing.T) {
+	client := models.Client{Email: "cliente@x.com", Phone: "(11) 98765-4321"}
+	client.NormalizePhone()
+	ct := models.ClientContact{Primary: true, Email: "antigo@x.com"}
+	if !ct.SyncFrom(&client) {
+		t.Fatal("SyncFrom deveria indicar alteração")
+	}
+	if ct.Email != client.Email || ct.Phone != client.Phone || ct.PhoneE164 != "+5511987654321" {
+		t.Errorf("contato não espelha o cliente: %+v", ct)
+	}
+	if ct.SyncFrom(&client) {
+		t.Error("SyncFrom sem mudança no cliente não deveria indicar alteração")
+	}
+}
@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	"reflect"
+	"testing"
+
+	"minha-api/models"
+	"minha-api/utils"
+)
+
+func TestApplyCompanyInfo(t *testing.T) {
+	info := &utils.CompanyInfo{
+		LegalName: "ACME COMERCIO LTDA", Email: "contato@acme.com.br", Phone: "1133334444",
+		Street: "RUA DAS FLORES", Number: "100", Neighborhood: "CENTRO", City: "SAO PAULO", UF: "SP", CEP: "01001000",
+	}
+
+	// Cliente só com CNPJ: tudo é preenchido
+	c := models.Client{Name: "Acme", Document: "11222333000181"}
+	changed := c.ApplyCompanyInfo(info, false)
+	esperado := []string{"email", "phone", "address_parts.street", "address_parts.number", "address_parts.neighborhood",
+		"address_parts.city", "address_parts.uf", "address_parts.cep", "address"}
+	if !reflect.DeepEqual(changed, esperado) {
+		t.Errorf("campos alterados: esperado %v, obteve %v", esperado, changed)
+	}
+	if c.Name != "Acme" || c.Email != "contato@acme.com.br" || c.AddressParts.City != "SAO PAULO" {
+		t.Errorf("cliente não preenchido como esperado: %+v", c)
+	}
+
+	// Campos preenchidos e endereço existente são mantidos sem overwrite
+	c = models.Client{Name: "Acme", Email: "vendas@acme.com.br", Address: "Av. Brasil, 1 - Rio de Janeiro/RJ"}
+	if changed := c.ApplyCompanyInfo(info, false); !reflect.DeepEqual(changed, []string{"phone"}) {
+		t.Errorf("sem overwrite: esperado só phone, obteve %v", changed)
+	}
+	if c.Email != "vendas@acme.com.br" || c.Address != "Av. Brasil, 1 - Rio de Janeiro/RJ" {
+		t.Errorf("sem overwrite: dados do cliente alterados: %+v", c)
+	}
+
+	// Com overwrite o cadastro prevalece
+	changed = c.ApplyCompanyInfo(info, true)
+	if c.Name != "ACME COMERCIO LTDA" || c.Email != "contato@acme.com.br" || c.AddressParts.Street != "RUA DAS FLORES" || c.Address != "" {
+		t.Errorf("com overwrite: cliente não substituído: %+v", c)
+	}
+	if len(changed) == 0 || changed[0] != "name" {
+		t.Errorf("com overwrite: campos alterados inesperados: %v", changed)
+	}
+}
@@ -0,0 +1,85 @@
+package models_test
+
+import (
+	"testing"
+
+	"minha-api/models"
+)
+
+// todosOsPares gera os pares candidatos como o banco geraria se todos fossem parecidos
+func todosOsPares(clientes []models.Client) [][2]models.Client {
+	var pares [][2]models.Client
+	for i := range clientes {
+		for j := i + 1; j < len(clientes); j++ {
+			pares = append(pares, [2]models.Client{clientes[i], clientes[j]})
+		}
+	}
+	return pares
+}
+
+func TestScoreDuplicateCandidates(t *testing.T) {
+	clientes := []models.Client{
+		{ID: "1", Name: "ACME Comércio Ltda", Email: "contato@acme.com"},
+		{ID: "2", Name: "Acme Comercio LTDA.", Email: "CONTATO@acme.com"},
+		{ID: "3", Name: "Zeta Transportes", Document: "11222333000181"},
+		{ID: "4", Name: "Zeta Transportes ME", Document: "11222333000181"},
+		{ID: "5", Name: "Padaria Pão Quente"},
+	}
+	duplicados := models.ScoreDuplicateCandidates(todosOsPares(clientes), 0.6)
+	if len(duplicados) != 2 {
+		t.Fatalf("esperava 2 pares, obteve %d: %+v", len(duplicados), duplicados)
+	}
+	pares := map[string]bool{}
+	for _, d := range duplicados {
+		pares[d.A.ID+"-"+d.B.ID] = true
+		if d.Score < 0.8 {
+			t.Errorf("par %s-%s: nota %v, esperado >= 0.8", d.A.ID, d.B.ID, d.Score)
+		}
+	}
+	if !pares["1-2"] || !pares["3-4"] {
+		t.Errorf("pares encontrados: %v", pares)
+	}
+
+	// Só o nome parecido, sem dado de contato em comum, não passa de 0.5
+	semContato := []models.Client{{ID: "1", Name: "ACME Ltda"}, {ID: "2", Name: "Acme"}}
+	if d := models.ScoreDuplicateCandidates(todosOsPares(semContato), 0.6); len(d) != 0 {
+		t.Errorf("nome igual sem contato em comum não deveria atingir 0.6: %+v", d)
+	}
+}
+
+func TestMergeClients(t *testing.T) {
+	keep := models.Client{ID: "1", Name: "ACME Ltda", Email: "", Phone: "11 98765-4321",
+		AddressParts: models.Address{Street: "Rua A", Number: "1", City: "São Paulo", UF: "SP"}}
+	remove := models.Client{ID: "2", Name: "Acme Comércio Ltda", Email: "contato@acme.com", Phone: "11 3333-4444",
+		AddressParts: models.Address{Street: "Rua B", Number: "2", Neighborhood: "Centro", City: "Campinas", UF: "SP"}}
+
+	// Sem escolhas: vale o de keep, ou o de remove quando keep está vazio
+	merged, errs := models.MergeClients(&keep, &remove, nil)
+	if errs != nil {
+		t.Fatalf("erro inesperado: %v", errs)
+	}
+	if merged.ID != "1" || merged.Name != "ACME Ltda" || merged.Email != "contato@acme.com" || merged.Phone != "11 98765-4321" {
+		t.Errorf("mesclagem padrão: %+v", merged)
+	}
+	// O endereço de keep vem inteiro, sem o bairro de remove
+	if merged.AddressParts != keep.AddressParts {
+		t.Errorf("endereço misturado: %+v", merged.AddressParts)
+	}
+
+	merged, errs = models.MergeClients(&keep, &remove, map[string]string{"name": "remove", "phone": "remove", "address": "remove"})
+	if errs != nil {
+		t.Fatalf("erro inesperado: %v", errs)
+	}
+	if merged.Name != remove.Name || merged.Phone != remove.Phone || merged.AddressParts != remove.AddressParts || merged.ID != "1" {
+		t.Errorf("mesclagem com escolhas: %+v", merged)
+	}
+
+	_, errs = models.MergeClients(&keep, &remove, map[string]string{"cpf": "keep", "email": "ambos", "address_parts.city": "remove"})
+	campos := map[string]bool{}
+	for _, e := range errs {
+		campos[e.Field] = true
+	}
+	if len(errs) != 3 || !campos["fields.cpf"] || !campos["fields.email"] || !campos["fields.address_parts.city"] {
+		t.Errorf("erros de validação: %+v", errs)
+	}
+}
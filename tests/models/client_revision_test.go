@@ -0,0 +1,33 @@
+package models_test
+
+import (
+	"reflect"
+	"testing"
+
+	"minha-api/models"
+)
+
+func TestDiffClients(t *testing.T) {
+	antes := models.Client{ID: "1", Name: "Ana", Email: "ana@antigo.com", Phone: "11999990000"}
+	depois := antes
+	depois.Email = "ana@novo.com"
+	depois.AddressParts.City = "Porto Alegre"
+
+	esperado := []models.FieldChange{
+		{Field: "email", Old: "ana@antigo.com", New: "ana@novo.com"},
+		{Field: "address_parts.city", Old: "", New: "Porto Alegre"},
+	}
+	if got := models.DiffClients(&antes, &depois); !reflect.DeepEqual(got, esperado) {
+		t.Errorf("DiffClients: esperado %v, obteve %v", esperado, got)
+	}
+
+	if got := models.DiffClients(&antes, &antes); len(got) != 0 {
+		t.Errorf("cliente sem alterações: esperado diff vazio, obteve %v", got)
+	}
+
+	// Criação: todos os campos preenchidos aparecem com valor antigo vazio
+	criado := models.DiffClients(&models.Client{}, &antes)
+	if len(criado) != 3 || criado[0] != (models.FieldChange{Field: "name", Old: "", New: "Ana"}) {
+		t.Errorf("diff de criação inesperado: %v", criado)
+	}
+}
@@ -0,0 +1,24 @@
+package models_test
+
+import (
+	"testing"
+
+	"minha-api/models"
+)
+
+func TestExportJobProgress(t *testing.T) {
+	casos := []struct {
+		job      models.ExportJob
+		esperado float64
+	}{
+		{models.ExportJob{Status: models.ExportJobPending}, 0},
+		{models.ExportJob{Status: models.ExportJobRunning, Total: 4000, Processed: 1000}, 0.25},
+		{models.ExportJob{Status: models.ExportJobDone, Total: 0}, 1}, // filtro sem clientes
+		{models.ExportJob{Status: models.ExportJobExpired, Total: 10, Processed: 10}, 1},
+	}
+	for _, c := range casos {
+		if got := c.job.Progress(); got != c.esperado {
+			t.Errorf("Progress(%s, %d/%d) = %v, esperado %v", c.job.Status, c.job.Processed, c.job.Total, got, c.esperado)
+		}
+	}
+}
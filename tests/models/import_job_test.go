@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	"testing"
+
+	"minha-api/models"
+)
+
+func TestImportJobRollbackable(t *testing.T) {
+	casos := []struct {
+		status   string
+		esperado bool
+	}{
+		{models.ImportJobProcessing, false},
+		{models.ImportJobCompleted, true},
+		{models.ImportJobInterrupted, true}, // as linhas anteriores ao limite foram gravadas
+		{models.ImportJobCancelled, false},
+		{models.ImportJobRolledBack, false},
+	}
+	for _, c := range casos {
+		job := models.ImportJob{Status: c.status}
+		if got := job.Rollbackable(); got != c.esperado {
+			t.Errorf("Rollbackable(%s) = %v, esperado %v", c.status, got, c.esperado)
+		}
+	}
+}
+
+func TestImportJobRollbackSkipReason(t *testing.T) {
+	job := models.ImportJob{ID: "job-1"}
+	casos := []struct {
+		nome     string
+		current  models.Client
+		later    *models.ClientRevision
+		esperado string
+	}{
+		{"sem alterações depois do job", models.Client{ImportJobID: "job-1"}, nil, ""},
+		{"gravado por último por outro job", models.Client{ImportJobID: "job-2"}, nil, "alterado por outra importação"},
+		{"revisão posterior de outro job", models.Client{ImportJobID: "job-1"}, &models.ClientRevision{ImportJobID: "job-2"}, "alterado por outra importação"},
+		{"edição manual posterior", models.Client{ImportJobID: "job-1"}, &models.ClientRevision{}, "alterado depois da importação"},
+	}
+	for _, c := range casos {
+		if got := job.RollbackSkipReason(&c.current, c.later); got != c.esperado {
+			t.Errorf("%s: esperado %q, obteve %q", c.nome, c.esperado, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"minha-api/models"
+)
+
+func TestTagValidate(t *testing.T) {
+	tag := models.Tag{Name: "  região   sul "}
+	if errs := tag.Validate(); errs != nil {
+		t.Fatalf("tag válida retornou erros: %v", errs)
+	}
+	if tag.Name != "região sul" {
+		t.Errorf("nome não normalizado: %q", tag.Name)
+	}
+
+	casos := []string{"   ", strings.Repeat("x", 51)}
+	for _, nome := range casos {
+		tag := models.Tag{Name: nome}
+		if errs := tag.Validate(); errs == nil || errs[0].Field != "name" {
+			t.Errorf("Validate(%q): esperado erro em name, obteve %v", nome, errs)
+		}
+	}
+}
+
+func TestSegmentValidate(t *testing.T) {
+	s := models.Segment{Name: " VIPs do Sul ", Filter: " tag=VIP&address_parts.uf[in]=RS,SC,PR "}
+	if errs := s.Validate(); errs != nil {
+		t.Fatalf("segmento válido retornou erros: %v", errs)
+	}
+	if s.Name != "VIPs do Sul" || s.Filter != "tag=VIP&address_parts.uf[in]=RS,SC,PR" {
+		t.Errorf("segmento não normalizado: %+v", s)
+	}
+
+	s = models.Segment{Name: "Sem filtro"}
+	if errs := s.Validate(); errs == nil || errs[0].Field != "filter" {
+		t.Errorf("esperado erro em filter, obteve %v", errs)
+	}
+}
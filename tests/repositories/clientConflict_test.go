@@ -0,0 +1,48 @@
+package repositories_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"minha-api/repositories"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestClientConflictFromError(t *testing.T) {
+	casos := []struct {
+		nome     string
+		err      error
+		esperado string // campo do conflito; vazio quando o erro não é conflito de cliente
+	}{
+		{"índice de documento", &pgconn.PgError{Code: "23505", ConstraintName: "idx_clients_document_unique"}, "document"},
+		{"índice de email", &pgconn.PgError{Code: "23505", ConstraintName: "idx_clients_email_unique"}, "email"},
+		{"erro embrulhado", fmt.Errorf("ao gravar: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_clients_email_unique"}), "email"},
+		{"outro índice único", &pgconn.PgError{Code: "23505", ConstraintName: "clients_pkey"}, ""},
+		{"outro código", &pgconn.PgError{Code: "23503", ConstraintName: "idx_clients_document_unique"}, ""},
+		{"erro comum", errors.New("conexão perdida"), ""},
+		{"sem erro", nil, ""},
+	}
+	for _, c := range casos {
+		conflict := repositories.ClientConflictFromError(c.err)
+		switch {
+		case c.esperado == "" && conflict != nil:
+			t.Errorf("%s: esperado nil (erro 500), obteve conflito em %q", c.nome, conflict.Field)
+		case c.esperado != "" && (conflict == nil || conflict.Field != c.esperado):
+			t.Errorf("%s: esperado conflito em %q, obteve %+v", c.nome, c.esperado, conflict)
+		}
+	}
+}
+
+func TestClientConflictErrorMessage(t *testing.T) {
+	casos := map[string]repositories.ClientConflictError{
+		"Já existe um cliente com este documento": {Field: "document"},
+		"Já existe o cliente abc com este e-mail": {Field: "email", ClientID: "abc"},
+	}
+	for esperado, conflict := range casos {
+		if got := conflict.Error(); got != esperado {
+			t.Errorf("Error() = %q, esperado %q", got, esperado)
+		}
+	}
+}
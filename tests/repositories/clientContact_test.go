@@ -0,0 +1,73 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+)
+
+func contatoPrincipal(t *testing.T, repo *repositories.ClientRepository, clientID string) *models.ClientContact {
+	t.Helper()
+	contacts, err := repo.Contacts(clientID)
+	if err != nil {
+		t.Fatalf("erro ao listar contatos: %v", err)
+	}
+	if len(contacts) == 0 || !contacts[0].Primary {
+		return nil
+	}
+	return &contacts[0]
+}
+
+func TestClientCreateAndUpdateSyncPrimaryContact(t *testing.T) {
+	testutils.TestDatabase(t)
+	repo := repositories.NewClientRepository()
+
+	client := models.Client{Name: "Contato Sincronizado", Document: testutils.RandomCNPJ(), Email: testutils.RandomEmail("sync"), Phone: "(11) 98765-4321"}
+	client.NormalizePhone()
+	if err := repo.Create(&client); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ct := contatoPrincipal(t, repo, client.ID)
+	if ct == nil || ct.Email != client.Email || ct.PhoneE164 != "+5511987654321" {
+		t.Fatalf("Create não criou o contato principal com os dados do cliente: %+v", ct)
+	}
+
+	client.Email = testutils.RandomEmail("sync-novo")
+	client.Phone = "(21) 98888-7777"
+	client.NormalizePhone()
+	if err := repo.Update(&client); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	atualizado := contatoPrincipal(t, repo, client.ID)
+	if atualizado == nil || atualizado.ID != ct.ID || atualizado.Email != client.Email || atualizado.PhoneE164 != "+5521988887777" {
+		t.Errorf("Update não atualizou o contato principal: %+v", atualizado)
+	}
+}
+
+func TestSaveContactPhoneOnlyKeepsClientEmail(t *testing.T) {
+	testutils.TestDatabase(t)
+	repo := repositories.NewClientRepository()
+
+	email := testutils.RandomEmail("so-telefone")
+	client := models.Client{Name: "Contato Só Telefone", Document: testutils.RandomCNPJ(), Email: email}
+	if err := repo.Create(&client); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ct := models.ClientContact{ClientID: client.ID, Type: models.ContactBilling, Name: "Financeiro", Phone: "(11) 3333-4444", Primary: true}
+	ct.Validate()
+	if err := repo.SaveContact(&ct); err != nil {
+		t.Fatalf("SaveContact: %v", err)
+	}
+	got, err := repo.GetByID(client.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Email != email {
+		t.Errorf("contato principal só com telefone apagou o email do cliente: %q", got.Email)
+	}
+	if got.PhoneE164 != "+551133334444" {
+		t.Errorf("telefone do contato principal não aplicado ao cliente: %q", got.PhoneE164)
+	}
+}
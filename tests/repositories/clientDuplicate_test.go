@@ -0,0 +1,57 @@
+package repositories_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+
+	"github.com/google/uuid"
+)
+
+func TestDuplicateCandidates(t *testing.T) {
+	testutils.TestDatabase(t)
+	repo := repositories.NewClientRepository()
+	sufixo := uuid.New().String()[:8]
+	criar := func(nome, telefone string) models.Client {
+		c := models.Client{Name: nome, Phone: telefone, Document: testutils.RandomCNPJ()}
+		c.NormalizePhone()
+		if err := repo.Create(&c); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return c
+	}
+	mesmoTelefoneA := criar("Padaria "+sufixo, fmt.Sprintf("(11) 3%03d-%04d", rand.Intn(1000), rand.Intn(10000)))
+	mesmoTelefoneB := criar("Outro Nome "+sufixo, mesmoTelefoneA.Phone)
+	nomeA := criar("Transportadora Zeta "+sufixo, "")
+	nomeB := criar("Transportadora Zeta "+sufixo+" Ltda", "")
+
+	candidatos := func(byName bool) map[[2]string]bool {
+		pares := map[[2]string]bool{}
+		err := repo.DuplicateCandidates(byName, 2, func(pairs [][2]models.Client) error {
+			for _, p := range pairs {
+				pares[[2]string{p[0].ID, p[1].ID}] = true
+				pares[[2]string{p[1].ID, p[0].ID}] = true
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("DuplicateCandidates: %v", err)
+		}
+		return pares
+	}
+
+	pares := candidatos(false)
+	if !pares[[2]string{mesmoTelefoneA.ID, mesmoTelefoneB.ID}] {
+		t.Error("par com o mesmo telefone não foi gerado")
+	}
+	if pares[[2]string{nomeA.ID, nomeB.ID}] {
+		t.Error("par só com nome parecido gerado sem byName")
+	}
+	if !candidatos(true)[[2]string{nomeA.ID, nomeB.ID}] {
+		t.Error("par com nome parecido não foi gerado com byName")
+	}
+}
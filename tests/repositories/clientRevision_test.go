@@ -0,0 +1,112 @@
+package repositories_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+)
+
+func TestConcurrentUpdatesRecordSequentialRevisions(t *testing.T) {
+	testutils.TestDatabase(t)
+	repo := repositories.NewClientRepository()
+	client := models.Client{Name: "Concorrente", Document: testutils.RandomCNPJ()}
+	if err := repo.Create(&client); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	const gravacoes = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, gravacoes)
+	for i := 0; i < gravacoes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := client
+			c.Name = fmt.Sprintf("Concorrente %d", i)
+			errs <- repo.Update(&c)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Update concorrente falhou: %v", err)
+		}
+	}
+
+	history, err := repo.History(client.ID)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	for i, rev := range history {
+		if esperado := len(history) - i; rev.Version != esperado {
+			t.Errorf("versões fora de sequência: posição %d tem versão %d, esperado %d", i, rev.Version, esperado)
+		}
+	}
+}
+
+func TestRestoreInvalidDocument(t *testing.T) {
+	db := testutils.TestDatabase(t)
+	repo := repositories.NewClientRepository()
+	client := models.Client{Name: "Documento Inválido", Document: testutils.RandomCNPJ()}
+	if err := repo.Create(&client); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	invalido := client
+	invalido.Document = "12345678000100"
+	rev := models.ClientRevision{ClientID: client.ID, Version: 2, Action: models.RevisionUpdated, Snapshot: &invalido, Actor: "teste"}
+	if err := db.Create(&rev).Error; err != nil {
+		t.Fatalf("erro ao gravar revisão: %v", err)
+	}
+
+	if _, err := repo.Restore(client.ID, 2); !errors.Is(err, repositories.ErrRevisionInvalid) {
+		t.Errorf("esperado ErrRevisionInvalid, obteve %v", err)
+	}
+	got, err := repo.GetByID(client.ID)
+	if err != nil || got.Document != client.Document {
+		t.Errorf("cliente alterado por restauração inválida: %+v, %v", got, err)
+	}
+}
+
+func TestSaveImportedPartsFromCurrentData(t *testing.T) {
+	testutils.TestDatabase(t)
+	repo := repositories.NewClientRepository()
+	client := models.Client{Name: "Importado", Document: testutils.RandomCNPJ()}
+	if err := repo.Create(&client); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Edição feita depois que a importação leu o cliente
+	editado := client
+	editado.Email = testutils.RandomEmail("editado")
+	if err := repo.Update(&editado); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	job := &models.ImportJob{FileName: "clientes.csv", Status: models.ImportJobProcessing}
+	if err := repositories.NewImportJobRepository().Create(job); err != nil {
+		t.Fatalf("erro ao criar job: %v", err)
+	}
+	linha := models.Client{Name: "Importado Novo Nome"}
+	saved, err := repo.SaveImported(job.ID, client.ID, 2, func(current *models.Client) error {
+		models.MergeClientFields(current, &linha, false)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SaveImported: %v", err)
+	}
+	if saved.Email != editado.Email || saved.Name != linha.Name {
+		t.Errorf("importação desfez a edição concorrente: %+v", saved)
+	}
+	history, err := repo.History(client.ID)
+	if err != nil || len(history) == 0 {
+		t.Fatalf("History: %v", err)
+	}
+	if changes := history[0].Changes; len(changes) != 1 || changes[0].Field != "name" {
+		t.Errorf("revisão deveria ter só a troca de nome, tem %+v", changes)
+	}
+}
@@ -0,0 +1,158 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+
+	"github.com/google/uuid"
+)
+
+// importacaoConcluida grava um job concluído que inseriu inserted e atualizou updated (com os dados anteriores)
+func importacaoConcluida(t *testing.T, inserted []models.Client, updated []*models.Client) *models.ImportJob {
+	t.Helper()
+	jobs := repositories.NewImportJobRepository()
+	clients := repositories.NewClientRepository()
+	job := &models.ImportJob{FileName: "clientes.csv", Status: models.ImportJobProcessing}
+	if err := jobs.Create(job); err != nil {
+		t.Fatalf("erro ao criar job: %v", err)
+	}
+	lines := make([]int, len(inserted))
+	for i := range inserted {
+		inserted[i].ID, inserted[i].ImportJobID, lines[i] = uuid.New().String(), job.ID, i+2
+	}
+	if len(inserted) > 0 {
+		if err := clients.CreateImported(job.ID, inserted, lines, 100); err != nil {
+			t.Fatalf("CreateImported: %v", err)
+		}
+	}
+	for i, c := range updated {
+		saved, err := clients.SaveImported(job.ID, c.ID, len(inserted)+i+2, func(current *models.Client) error {
+			models.MergeClientFields(current, c, false)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("SaveImported: %v", err)
+		}
+		*c = saved
+	}
+	now := time.Now()
+	job.Status, job.FinishedAt = models.ImportJobCompleted, &now
+	if err := jobs.Update(job); err != nil {
+		t.Fatalf("erro ao concluir job: %v", err)
+	}
+	return job
+}
+
+func novoCliente(t *testing.T, nome string) models.Client {
+	t.Helper()
+	c := models.Client{Name: nome, Document: testutils.RandomCNPJ(), Email: testutils.RandomEmail("rollback")}
+	if err := repositories.NewClientRepository().Create(&c); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return c
+}
+
+func TestRollbackDeletesInsertedAndRestoresUpdated(t *testing.T) {
+	testutils.TestDatabase(t)
+	clients := repositories.NewClientRepository()
+	existente := novoCliente(t, "Existente")
+	alterado := existente
+	alterado.Name = "Existente Importado"
+	inserido := models.Client{Name: "Inserido", Document: testutils.RandomCNPJ(), Email: testutils.RandomEmail("inserido")}
+	inseridos := []models.Client{inserido}
+	job := importacaoConcluida(t, inseridos, []*models.Client{&alterado})
+
+	result, err := repositories.NewImportJobRepository().Rollback(job, repositories.Audit{Actor: "teste"})
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if result.Deleted != 1 || result.Restored != 1 || len(result.Skipped) != 0 {
+		t.Errorf("resultado inesperado: %+v", result)
+	}
+	if _, err := clients.GetByID(inseridos[0].ID); err == nil {
+		t.Error("cliente inserido pela importação não foi excluído")
+	}
+	got, err := clients.GetByID(existente.ID)
+	if err != nil || got.Name != "Existente" {
+		t.Errorf("cliente atualizado não voltou aos dados anteriores: %+v, %v", got, err)
+	}
+	if job.Status != models.ImportJobRolledBack {
+		t.Errorf("job deveria ficar %q, ficou %q", models.ImportJobRolledBack, job.Status)
+	}
+}
+
+func TestRollbackSkipsClientsChangedLater(t *testing.T) {
+	testutils.TestDatabase(t)
+	clients := repositories.NewClientRepository()
+	porImportacao := novoCliente(t, "Alterado Depois Por Importação")
+	manual := novoCliente(t, "Editado Depois Manualmente")
+
+	a, b := porImportacao, manual
+	a.Name, b.Name = "Primeira Importação A", "Primeira Importação B"
+	job := importacaoConcluida(t, nil, []*models.Client{&a, &b})
+
+	// Outra importação altera um dos clientes; o outro é editado pela API (Update mantém o ImportJobID)
+	segunda := a
+	segunda.Name = "Segunda Importação"
+	importacaoConcluida(t, nil, []*models.Client{&segunda})
+	editado := b
+	editado.Name = "Editado à Mão"
+	if err := clients.WithAudit(repositories.Audit{Actor: "usuario"}).Update(&editado); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	result, err := repositories.NewImportJobRepository().Rollback(job, repositories.Audit{Actor: "teste"})
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if result.Restored != 0 || len(result.Skipped) != 2 {
+		t.Fatalf("esperado 2 clientes mantidos e nenhum restaurado, obteve %+v", result)
+	}
+	motivos := map[string]string{}
+	for _, s := range result.Skipped {
+		motivos[s.ClientID] = s.Reason
+	}
+	if motivos[a.ID] != "alterado por outra importação" || motivos[b.ID] != "alterado depois da importação" {
+		t.Errorf("motivos inesperados: %v", motivos)
+	}
+	if got, _ := clients.GetByID(b.ID); got.Name != "Editado à Mão" {
+		t.Errorf("rollback desfez a edição manual: %q", got.Name)
+	}
+	if got, _ := clients.GetByID(a.ID); got.Name != "Segunda Importação" {
+		t.Errorf("rollback desfez a importação posterior: %q", got.Name)
+	}
+}
+
+func TestRollbackKeepsContactsOfSkippedClients(t *testing.T) {
+	testutils.TestDatabase(t)
+	clients := repositories.NewClientRepository()
+	// Sem email nem telefone: o contato principal é criado pela importação, associado ao job
+	semContato := models.Client{Name: "Sem Contato", Document: testutils.RandomCNPJ()}
+	if err := clients.Create(&semContato); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	importado := semContato
+	importado.Email = testutils.RandomEmail("contato")
+	job := importacaoConcluida(t, nil, []*models.Client{&importado})
+
+	editado := importado
+	editado.Name = "Editado à Mão"
+	if err := clients.WithAudit(repositories.Audit{Actor: "usuario"}).Update(&editado); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	result, err := repositories.NewImportJobRepository().Rollback(job, repositories.Audit{Actor: "teste"})
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if len(result.Skipped) != 1 {
+		t.Fatalf("esperado 1 cliente mantido, obteve %+v", result)
+	}
+	contatos, err := clients.Contacts(semContato.ID)
+	if err != nil || len(contatos) != 1 || contatos[0].Email != importado.Email {
+		t.Errorf("cliente mantido perdeu o contato criado pela importação: %+v, %v", contatos, err)
+	}
+}
@@ -0,0 +1,55 @@
+package repositories_test
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"minha-api/models"
+	"minha-api/repositories"
+	"minha-api/tests/testutils"
+	"minha-api/utils"
+
+	"github.com/google/uuid"
+)
+
+func TestListCursorIncludesNullRows(t *testing.T) {
+	db := testutils.TestDatabase(t)
+	repo := repositories.NewClientRepository()
+	prefixo := "Paginacao " + uuid.New().String()[:8]
+	ids := map[string]bool{}
+	for i, cidade := range []string{"Campinas", "", "Santos"} {
+		c := models.Client{Name: fmt.Sprintf("%s %d", prefixo, i), Document: testutils.RandomCNPJ()}
+		c.AddressParts.City = cidade
+		if err := repo.Create(&c); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if cidade == "" {
+			db.Exec("UPDATE clients SET address_city = NULL WHERE id = ?", c.ID)
+		}
+		ids[c.ID] = true
+	}
+
+	vistos := map[string]bool{}
+	q := url.Values{"name[ilike]": {prefixo}, "sort": {"address_parts.city"}, "limit": {"1"}}
+	for pagina := 0; pagina < 5; pagina++ {
+		lq, errs := utils.ParseListQuery(q, repositories.ClientListSpec)
+		if errs != nil {
+			t.Fatalf("ParseListQuery: %v", errs)
+		}
+		result, err := repo.List(repositories.ClientFilter{}, lq)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, c := range result.Items {
+			vistos[c.ID] = true
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		q.Set("cursor", result.NextCursor)
+	}
+	if len(vistos) != len(ids) {
+		t.Errorf("paginação por cursor retornou %d de %d clientes (linha com cidade NULL perdida?)", len(vistos), len(ids))
+	}
+}
@@ -0,0 +1,75 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	middlewares "minha-api/middleware"
+	"minha-api/tests/testutils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routerComAutor responde com o autor da requisição, como registrado no histórico dos clientes
+func routerComAutor() *gin.Engine {
+	r := gin.New()
+	r.Use(middlewares.RequestContextMiddleware())
+	r.GET("/autor", middlewares.ApiKeyMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, middlewares.Actor(c))
+	})
+	return r
+}
+
+func TestApiKeyDefineOAutor(t *testing.T) {
+	t.Setenv("API_KEYS", "maria:chave-maria, joao:chave-joao")
+	r := routerComAutor()
+	casos := []struct {
+		chave    string
+		status   int
+		esperado string
+	}{
+		{"chave-maria", http.StatusOK, "maria"},
+		{"chave-joao", http.StatusOK, "joao"},
+		{middlewares.ApiKeyEsperada, http.StatusUnauthorized, ""}, // com API_KEYS a chave fixa não vale
+		{"", http.StatusUnauthorized, ""},
+	}
+	for _, c := range casos {
+		req, _ := http.NewRequest("GET", "/autor", nil)
+		req.Header.Set("X-API-Key", c.chave)
+		req.Header.Set("X-User", "forjado") // ignorado: o autor vem da chave
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != c.status || (c.status == http.StatusOK && w.Body.String() != c.esperado) {
+			t.Errorf("chave %q: esperado %d %q, obteve %d %q", c.chave, c.status, c.esperado, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestApiKeyFixaSemApiKeys(t *testing.T) {
+	t.Setenv("API_KEYS", "")
+	req, _ := http.NewRequest("GET", "/autor", nil)
+	req.Header.Set("X-API-Key", middlewares.ApiKeyEsperada)
+	w := httptest.NewRecorder()
+	routerComAutor().ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != middlewares.ApiKeyUser {
+		t.Errorf("esperado 200 %q, obteve %d %q", middlewares.ApiKeyUser, w.Code, w.Body.String())
+	}
+}
+
+func TestClientRoutesExigemApiKey(t *testing.T) {
+	r := testutils.SetupClientRouter()
+	for _, rota := range []struct{ method, path string }{
+		{"POST", "/clients"},
+		{"PUT", "/clients/00000000-0000-0000-0000-000000000000"},
+		{"POST", "/clients/upload"},
+		{"POST", "/import-templates"},
+	} {
+		req, _ := http.NewRequest(rota.method, rota.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s sem API key: esperado 401, obteve %d", rota.method, rota.path, w.Code)
+		}
+	}
+}
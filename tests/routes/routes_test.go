@@ -37,3 +37,24 @@ func TestBooksRoutes(t *testing.T) {
 		t.Fatalf("POST /books: resposta sem id")
 	}
 }
+
+func TestRequestIDHeader(t *testing.T) {
+	r := setupRouterWithMocks()
+
+	req, _ := http.NewRequest("GET", "/books", nil)
+	req.Header.Set("X-API-Key", "minha-chave-secreta")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Header().Get("X-Request-ID") == "" {
+		t.Errorf("esperado X-Request-ID gerado na resposta")
+	}
+
+	req, _ = http.NewRequest("GET", "/books", nil)
+	req.Header.Set("X-API-Key", "minha-chave-secreta")
+	req.Header.Set("X-Request-ID", "req-123")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get("X-Request-ID"); got != "req-123" {
+		t.Errorf("X-Request-ID: esperado req-123, obteve %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package testutils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// FakeCompanyRegistry é um servidor HTTP local que responde como a BrasilAPI (GET /{cnpj}), para testar o
+// cadastro de empresas sem acesso à rede. Use URL como COMPANY_REGISTRY_URL ou em utils.NewHTTPCompanyRegistry.
+type FakeCompanyRegistry struct {
+	*httptest.Server
+	mu          sync.Mutex
+	companies   map[string]map[string]interface{}
+	requests    int
+	rateLimited bool
+}
+
+// NewFakeCompanyRegistry inicia o servidor. Chame Close ao terminar.
+func NewFakeCompanyRegistry() *FakeCompanyRegistry {
+	f := &FakeCompanyRegistry{companies: map[string]map[string]interface{}{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+// Add cadastra uma empresa com os campos da BrasilAPI (razao_social, email, logradouro, municipio...)
+func (f *FakeCompanyRegistry) Add(cnpj string, company map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.companies[cnpj] = company
+}
+
+// SetRateLimited faz o servidor responder 429 com Retry-After enquanto limited for true
+func (f *FakeCompanyRegistry) SetRateLimited(limited bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rateLimited = limited
+}
+
+// Requests retorna quantas consultas o servidor recebeu
+func (f *FakeCompanyRegistry) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func (f *FakeCompanyRegistry) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	company, ok := f.companies[strings.Trim(r.URL.Path, "/")]
+	limited := f.rateLimited
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case limited:
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Too many requests"})
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"message": "CNPJ não encontrado"})
+	default:
+		json.NewEncoder(w).Encode(company)
+	}
+}
@@ -1,6 +1,8 @@
 package testutils
 
 import (
+	"minha-api/controllers"
+	middlewares "minha-api/middleware"
 	"minha-api/repositories"
 	"minha-api/routes"
 	"minha-api/utils"
@@ -27,3 +29,25 @@ func SetupRouterWithReposAndS3(bookRepo repositories.BookRepositoryInterface, fi
 func GetFileProcessRepositoryMock() *repositories.FileProcessRepositoryMock {
 	return repositories.NewFileProcessRepositoryMock()
 }
+
+// SetupClientRouter monta as rotas de clientes, importação e templates de importação com os repositórios
+// reais (database.DB), sem S3, cadastro de empresas nem o worker de exportação. Como em SetupRoutes, as rotas
+// exigem a API key (middlewares.ApiKeyEsperada). Use com TestDatabase.
+func SetupClientRouter() *gin.Engine {
+	r := gin.Default()
+	r.Use(middlewares.RequestContextMiddleware())
+	clientRepo := repositories.NewClientRepository()
+	templateRepo := repositories.NewImportTemplateRepository()
+	clientController := controllers.NewClientController(clientRepo, templateRepo, repositories.NewImportJobRepository(), nil)
+	clientCRUDController := controllers.NewClientCRUDController(clientRepo)
+	templateController := controllers.NewImportTemplateController(templateRepo)
+
+	api := r.Group("", middlewares.ApiKeyMiddleware())
+	api.POST("/clients/upload", clientController.UploadClients)
+	api.GET("/clients/:id", clientCRUDController.GetByID)
+	api.POST("/clients", clientCRUDController.Create)
+	api.PUT("/clients/:id", clientCRUDController.Update)
+	api.GET("/import-templates/:id", templateController.GetByID)
+	api.POST("/import-templates", templateController.Create)
+	return r
+}
@@ -0,0 +1,118 @@
+package utils_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"minha-api/tests/testutils"
+	"minha-api/utils"
+)
+
+const cnpjAcme = "11222333000181"
+
+func novoCadastroFake() *testutils.FakeCompanyRegistry {
+	fake := testutils.NewFakeCompanyRegistry()
+	fake.Add(cnpjAcme, map[string]interface{}{
+		"cnpj":                         cnpjAcme,
+		"razao_social":                 "ACME COMERCIO LTDA",
+		"nome_fantasia":                "ACME",
+		"descricao_situacao_cadastral": "ATIVA",
+		"email":                        "Contato@Acme.com.br",
+		"ddd_telefone_1":               "1133334444",
+		"descricao_tipo_de_logradouro": "RUA",
+		"logradouro":                   "DAS FLORES",
+		"numero":                       "100",
+		"bairro":                       "CENTRO",
+		"municipio":                    "SAO PAULO",
+		"uf":                           "sp",
+		"cep":                          "01001-000",
+	})
+	return fake
+}
+
+func TestHTTPCompanyRegistry(t *testing.T) {
+	fake := novoCadastroFake()
+	defer fake.Close()
+	registry := utils.NewHTTPCompanyRegistry(fake.URL, 6000)
+
+	info, err := registry.LookupCNPJ(context.Background(), "11.222.333/0001-81")
+	if err != nil {
+		t.Fatalf("LookupCNPJ: erro inesperado: %v", err)
+	}
+	esperado := utils.CompanyInfo{
+		CNPJ: cnpjAcme, LegalName: "ACME COMERCIO LTDA", TradeName: "ACME", Status: "ATIVA",
+		Email: "contato@acme.com.br", Phone: "1133334444", Street: "RUA DAS FLORES", Number: "100",
+		Neighborhood: "CENTRO", City: "SAO PAULO", UF: "SP", CEP: "01001000",
+	}
+	if *info != esperado {
+		t.Errorf("LookupCNPJ:\nesperado %+v\nobteve   %+v", esperado, *info)
+	}
+
+	if _, err := registry.LookupCNPJ(context.Background(), "99888777000100"); !errors.Is(err, utils.ErrCompanyNotFound) {
+		t.Errorf("CNPJ desconhecido: esperado ErrCompanyNotFound, obteve %v", err)
+	}
+
+	fake.SetRateLimited(true)
+	if _, err := registry.LookupCNPJ(context.Background(), cnpjAcme); !errors.Is(err, utils.ErrRateLimited) {
+		t.Errorf("resposta 429: esperado ErrRateLimited, obteve %v", err)
+	}
+	// Depois de um 429 nada é enviado antes do Retry-After
+	fake.SetRateLimited(false)
+	antes := fake.Requests()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := registry.LookupCNPJ(ctx, cnpjAcme); !errors.Is(err, utils.ErrRateLimited) {
+		t.Errorf("consulta durante a pausa: esperado ErrRateLimited, obteve %v", err)
+	}
+	if fake.Requests() != antes {
+		t.Errorf("consulta enviada durante a pausa do Retry-After")
+	}
+}
+
+func TestCachedCompanyRegistry(t *testing.T) {
+	fake := novoCadastroFake()
+	defer fake.Close()
+	registry := utils.NewCachedCompanyRegistry(utils.NewHTTPCompanyRegistry(fake.URL, 6000), 50*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		info, err := registry.LookupCNPJ(context.Background(), cnpjAcme)
+		if err != nil || info.LegalName != "ACME COMERCIO LTDA" {
+			t.Fatalf("consulta %d: obteve %+v, %v", i, info, err)
+		}
+		info.LegalName = "alterado" // alterar o retorno não pode afetar o cache
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := registry.LookupCNPJ(context.Background(), "99888777000100"); !errors.Is(err, utils.ErrCompanyNotFound) {
+			t.Fatalf("CNPJ desconhecido: esperado ErrCompanyNotFound, obteve %v", err)
+		}
+	}
+	if got := fake.Requests(); got != 2 {
+		t.Errorf("esperado 2 consultas ao servidor (uma por CNPJ), obteve %d", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := registry.LookupCNPJ(context.Background(), cnpjAcme); err != nil {
+		t.Fatalf("consulta após expirar: %v", err)
+	}
+	if got := fake.Requests(); got != 3 {
+		t.Errorf("esperado nova consulta após o TTL, total %d", got)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := utils.NewRateLimiter(60) // uma chamada por segundo
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("primeira chamada: erro inesperado: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	inicio := time.Now()
+	if err := limiter.Wait(ctx); !errors.Is(err, utils.ErrRateLimited) {
+		t.Errorf("segunda chamada antes do intervalo: esperado ErrRateLimited, obteve %v", err)
+	}
+	if time.Since(inicio) > 50*time.Millisecond {
+		t.Errorf("Wait esperou mesmo sem vaga dentro do prazo")
+	}
+}
@@ -0,0 +1,48 @@
+package utils_test
+
+import (
+	"testing"
+
+	"minha-api/utils"
+)
+
+func TestParseDocument(t *testing.T) {
+	casos := []struct {
+		entrada    string
+		tipo       string
+		digitos    string
+		formatado  string
+		tipoPessoa string
+	}{
+		{"529.982.247-25", utils.DocumentTypeCPF, "52998224725", "529.982.247-25", utils.PersonTypeIndividual},
+		{"52998224725", utils.DocumentTypeCPF, "52998224725", "529.982.247-25", utils.PersonTypeIndividual},
+		{"11.222.333/0001-81", utils.DocumentTypeCNPJ, "11222333000181", "11.222.333/0001-81", utils.PersonTypeCompany},
+		{" 11222333000181 ", utils.DocumentTypeCNPJ, "11222333000181", "11.222.333/0001-81", utils.PersonTypeCompany},
+	}
+	for _, c := range casos {
+		doc, err := utils.ParseDocument(c.entrada)
+		if err != nil {
+			t.Errorf("ParseDocument(%q): erro inesperado: %v", c.entrada, err)
+			continue
+		}
+		if doc.Type != c.tipo || doc.Digits != c.digitos || doc.Formatted() != c.formatado || doc.PersonType() != c.tipoPessoa {
+			t.Errorf("ParseDocument(%q): obteve %+v (%s, %s)", c.entrada, doc, doc.Formatted(), doc.PersonType())
+		}
+	}
+}
+
+func TestParseDocumentInvalido(t *testing.T) {
+	casos := map[string]error{
+		"529.982.247-24":     utils.ErrDocumentCheckDigit,
+		"111.111.111-11":     utils.ErrDocumentCheckDigit,
+		"11.222.333/0001-82": utils.ErrDocumentCheckDigit,
+		"00.000.000/0000-00": utils.ErrDocumentCheckDigit,
+		"1234":               utils.ErrDocumentLength,
+		"":                   utils.ErrDocumentLength,
+	}
+	for entrada, esperado := range casos {
+		if _, err := utils.ParseDocument(entrada); err != esperado {
+			t.Errorf("ParseDocument(%q): esperado erro %v, obteve %v", entrada, esperado, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package utils_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"minha-api/utils"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var colunasExportacao = []utils.ExportColumn{{Key: "name", Header: "Nome"}, {Key: "address_parts.city", Header: "Cidade"}}
+
+func exportar(t *testing.T, formato string, opcoes utils.ExportOptions) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	exp, err := utils.NewExporter(formato, &buf, colunasExportacao, opcoes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, linha := range [][]string{{"José \"Zé\" Silva", "São Paulo"}, {"Ana; Maria", ""}} {
+		if err := exp.WriteRow(linha); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExporterCSV(t *testing.T) {
+	esperado := "\xEF\xBB\xBFNome;Cidade\n\"José \"\"Zé\"\" Silva\";São Paulo\n\"Ana; Maria\";\n"
+	if got := string(exportar(t, utils.ExportCSV, utils.ExportOptions{BOM: true})); got != esperado {
+		t.Errorf("CSV padrão:\n%q\nesperado\n%q", got, esperado)
+	}
+	esperado = "Nome,Cidade\n\"José \"\"Zé\"\" Silva\",São Paulo\nAna; Maria,\n"
+	if got := string(exportar(t, utils.ExportCSV, utils.ExportOptions{Delimiter: ','})); got != esperado {
+		t.Errorf("CSV com vírgula e sem BOM:\n%q\nesperado\n%q", got, esperado)
+	}
+}
+
+func TestExporterJSONL(t *testing.T) {
+	esperado := `{"name":"José \"Zé\" Silva","address_parts.city":"São Paulo"}` + "\n" +
+		`{"name":"Ana; Maria","address_parts.city":""}` + "\n"
+	if got := string(exportar(t, utils.ExportJSONL, utils.ExportOptions{})); got != esperado {
+		t.Errorf("JSON Lines:\n%s\nesperado\n%s", got, esperado)
+	}
+}
+
+func TestExporterXLSX(t *testing.T) {
+	conteudo := exportar(t, utils.ExportXLSX, utils.ExportOptions{SheetName: "Clientes"})
+	f, err := excelize.OpenReader(bytes.NewReader(conteudo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if abas := f.GetSheetList(); !reflect.DeepEqual(abas, []string{"Clientes"}) {
+		t.Errorf("abas = %v", abas)
+	}
+	linhas, err := f.GetRows("Clientes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperado := [][]string{{"Nome", "Cidade"}, {"José \"Zé\" Silva", "São Paulo"}, {"Ana; Maria"}}
+	if !reflect.DeepEqual(linhas, esperado) {
+		t.Errorf("linhas = %q, esperado %q", linhas, esperado)
+	}
+}
+
+func TestExportFormatFromAccept(t *testing.T) {
+	casos := map[string]string{
+		"text/csv":                          utils.ExportCSV,
+		"application/x-ndjson":              utils.ExportJSONL,
+		"application/jsonl, text/csv;q=0.5": utils.ExportJSONL,
+		"text/html, text/csv":               utils.ExportCSV,
+		"*/*":                               utils.ExportXLSX,
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": utils.ExportXLSX,
+	}
+	for accept, esperado := range casos {
+		f, ok := utils.ExportFormatFromAccept(accept)
+		if !ok || f.Name != esperado {
+			t.Errorf("ExportFormatFromAccept(%q) = %q, %v; esperado %q", accept, f.Name, ok, esperado)
+		}
+	}
+	if _, ok := utils.ExportFormatFromAccept("text/html"); ok {
+		t.Error("text/html não deveria ser aceito")
+	}
+}
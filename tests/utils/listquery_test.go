@@ -0,0 +1,145 @@
+package utils_test
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"minha-api/utils"
+)
+
+var specLivros = utils.ListSpec{
+	Fields: map[string]utils.ListField{
+		"id":         {Column: "id", Type: utils.ListString},
+		"title":      {Column: "title", Type: utils.ListString},
+		"pages":      {Column: "pages", Type: utils.ListNumber},
+		"created_at": {Column: "created_at", Type: utils.ListTime},
+	},
+	DefaultSort: "-created_at", KeyField: "id", DefaultLimit: 20, MaxLimit: 100,
+}
+
+func TestParseListQuery(t *testing.T) {
+	q, _ := url.ParseQuery("title[ilike]=harry&pages[in]=100,200&created_at[gte]=2024-01-31&sort=title,-pages&limit=10&offset=30&apiKey=x")
+	lq, errs := utils.ParseListQuery(q, specLivros)
+	if errs != nil {
+		t.Fatalf("erros inesperados: %v", errs)
+	}
+	esperado := []utils.ListFilter{
+		{Field: "created_at", Column: "created_at", Op: utils.OpGte, Values: []interface{}{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}},
+		{Field: "pages", Column: "pages", Op: utils.OpIn, Values: []interface{}{100.0, 200.0}},
+		{Field: "title", Column: "title", Op: utils.OpILike, Values: []interface{}{"harry"}},
+	}
+	if !reflect.DeepEqual(lq.Filters, esperado) {
+		t.Errorf("filtros = %+v", lq.Filters)
+	}
+	ordem := []utils.ListSort{
+		{Field: "title", Column: "title", Type: utils.ListString},
+		{Field: "pages", Column: "pages", Type: utils.ListNumber, Desc: true},
+		{Field: "id", Column: "id", Type: utils.ListString}, // desempate
+	}
+	if !reflect.DeepEqual(lq.Sort, ordem) {
+		t.Errorf("ordenação = %+v", lq.Sort)
+	}
+	if lq.Limit != 10 || lq.Offset != 30 {
+		t.Errorf("limit/offset = %d/%d", lq.Limit, lq.Offset)
+	}
+
+	lq, errs = utils.ParseListQuery(url.Values{}, specLivros)
+	if errs != nil || lq.Limit != 20 || len(lq.Sort) != 2 || !lq.Sort[0].Desc || lq.Sort[0].Field != "created_at" {
+		t.Errorf("padrões: %+v, erros %v", lq, errs)
+	}
+}
+
+func TestParseListQueryErros(t *testing.T) {
+	casos := map[string]string{
+		"author[eq]=x":          "author[eq]",
+		"pages[ilike]=1":        "pages[ilike]",
+		"pages[gte]=muitas":     "pages[gte]",
+		"created_at[lte]=ontem": "created_at[lte]",
+		"sort=-author":          "sort",
+		"limit=0":               "limit",
+		"limit=101":             "limit",
+		"offset=-1":             "offset",
+		"cursor=lixo":           "cursor",
+		"cursor=abc&offset=10":  "cursor",
+	}
+	for entrada, campo := range casos {
+		q, _ := url.ParseQuery(entrada)
+		_, errs := utils.ParseListQuery(q, specLivros)
+		if len(errs) != 1 || errs[0].Field != campo {
+			t.Errorf("%s: esperava erro em %s, obteve %v", entrada, campo, errs)
+		}
+	}
+}
+
+func TestListCursorIdaEVolta(t *testing.T) {
+	q, _ := url.ParseQuery("sort=-created_at,pages")
+	lq, _ := utils.ParseListQuery(q, specLivros)
+	ultimo := []interface{}{time.Date(2024, 5, 1, 12, 30, 0, 123000, time.UTC), 250.0, "b3e1c2d0"}
+	cursor := utils.EncodeListCursor(lq.Sort, ultimo)
+
+	q.Set("cursor", cursor)
+	lq, errs := utils.ParseListQuery(q, specLivros)
+	if errs != nil {
+		t.Fatalf("erros inesperados: %v", errs)
+	}
+	if !reflect.DeepEqual(lq.After, ultimo) {
+		t.Errorf("After = %v, esperado %v", lq.After, ultimo)
+	}
+
+	// O cursor só vale para a ordenação em que foi gerado
+	q.Set("sort", "pages")
+	if _, errs := utils.ParseListQuery(q, specLivros); len(errs) != 1 || errs[0].Field != "cursor" {
+		t.Errorf("esperava erro de cursor com outra ordenação, obteve %v", errs)
+	}
+}
+
+func TestListCursorComValorNulo(t *testing.T) {
+	q, _ := url.ParseQuery("sort=-created_at,pages,title")
+	lq, _ := utils.ParseListQuery(q, specLivros)
+	// Último item com as colunas NULL: o cursor guarda o valor zero do tipo, o mesmo de ListSort.Expr
+	var semData *time.Time
+	var semPaginas *float64
+	cursor := utils.EncodeListCursor(lq.Sort, []interface{}{semData, semPaginas, nil, "b3e1c2d0"})
+
+	q.Set("cursor", cursor)
+	lq, errs := utils.ParseListQuery(q, specLivros)
+	if errs != nil {
+		t.Fatalf("cursor de item com NULL rejeitado: %v", errs)
+	}
+	esperado := []interface{}{time.Time{}, 0.0, "", "b3e1c2d0"}
+	if !reflect.DeepEqual(lq.After, esperado) {
+		t.Errorf("After = %v, esperado %v", lq.After, esperado)
+	}
+
+	exprs := map[string]string{
+		"created_at": "COALESCE(created_at, '0001-01-01T00:00:00Z')",
+		"pages":      "COALESCE(pages, 0)",
+		"title":      "COALESCE(title, '')",
+	}
+	for _, s := range lq.Sort {
+		if e, ok := exprs[s.Field]; ok && s.Expr() != e {
+			t.Errorf("Expr de %s = %q, esperado %q", s.Field, s.Expr(), e)
+		}
+	}
+}
+
+func TestListKeysetCondition(t *testing.T) {
+	q, _ := url.ParseQuery("sort=-created_at,pages")
+	lq, _ := utils.ParseListQuery(q, specLivros)
+	data := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	lq.After = []interface{}{data, 250.0, "b3e1c2d0"}
+
+	cond, args := lq.KeysetCondition()
+	criado, paginas, id := "COALESCE(created_at, '0001-01-01T00:00:00Z')", "COALESCE(pages, 0)", "COALESCE(id, '')"
+	esperado := "((" + criado + " < ?) OR (" + criado + " = ? AND " + paginas + " > ?) OR (" +
+		criado + " = ? AND " + paginas + " = ? AND " + id + " > ?))"
+	if cond != esperado {
+		t.Errorf("condição:\n%s\nesperado:\n%s", cond, esperado)
+	}
+	esperadoArgs := []interface{}{data, data, 250.0, data, 250.0, "b3e1c2d0"}
+	if !reflect.DeepEqual(args, esperadoArgs) {
+		t.Errorf("args = %v, esperado %v", args, esperadoArgs)
+	}
+}
@@ -0,0 +1,46 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"minha-api/utils"
+)
+
+// Exemplos do apêndice A da RFC 7396
+func TestMergePatch(t *testing.T) {
+	casos := []struct{ original, patch, esperado string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, c := range casos {
+		var original, patch, esperado interface{}
+		json.Unmarshal([]byte(c.original), &original)
+		json.Unmarshal([]byte(c.patch), &patch)
+		json.Unmarshal([]byte(c.esperado), &esperado)
+		if got := utils.MergePatch(original, patch); !reflect.DeepEqual(got, esperado) {
+			t.Errorf("MergePatch(%s, %s): esperado %s, obteve %v", c.original, c.patch, c.esperado, got)
+		}
+	}
+
+	// O documento original não é alterado
+	original := map[string]interface{}{"a": "b"}
+	utils.MergePatch(original, map[string]interface{}{"a": nil})
+	if original["a"] != "b" {
+		t.Errorf("MergePatch alterou o documento original: %v", original)
+	}
+}
@@ -0,0 +1,50 @@
+package utils_test
+
+import (
+	"testing"
+
+	"minha-api/utils"
+)
+
+func TestParsePhoneBR(t *testing.T) {
+	casos := []struct {
+		entrada   string
+		e164      string
+		tipo      string
+		formatado string
+	}{
+		{"(11) 98765-4321", "+5511987654321", utils.PhoneTypeMobile, "(11) 98765-4321"},
+		{"11987654321", "+5511987654321", utils.PhoneTypeMobile, "(11) 98765-4321"},
+		{"+55 11 98765-4321", "+5511987654321", utils.PhoneTypeMobile, "(11) 98765-4321"},
+		{"5511987654321", "+5511987654321", utils.PhoneTypeMobile, "(11) 98765-4321"},
+		{"(21) 3456-7890", "+552134567890", utils.PhoneTypeLandline, "(21) 3456-7890"},
+		{"021 3456-7890", "+552134567890", utils.PhoneTypeLandline, "(21) 3456-7890"},
+		{"(55) 99123-4567", "+5555991234567", utils.PhoneTypeMobile, "(55) 99123-4567"},
+		{"(31) 8765-4321", "+5531987654321", utils.PhoneTypeMobile, "(31) 98765-4321"}, // celular antigo sem o nono dígito
+	}
+	for _, c := range casos {
+		p, err := utils.ParsePhoneBR(c.entrada)
+		if err != nil {
+			t.Errorf("ParsePhoneBR(%q): erro inesperado: %v", c.entrada, err)
+			continue
+		}
+		if p.E164() != c.e164 || p.Type != c.tipo || p.Formatted() != c.formatado {
+			t.Errorf("ParsePhoneBR(%q): obteve %s %s %s", c.entrada, p.E164(), p.Type, p.Formatted())
+		}
+	}
+}
+
+func TestParsePhoneBRInvalido(t *testing.T) {
+	casos := map[string]error{
+		"(20) 98765-4321": utils.ErrPhoneDDD,
+		"(11) 1234-5678":  utils.ErrPhoneNumber,
+		"(11) 88765-4321": utils.ErrPhoneNumber,
+		"98765-4321":      utils.ErrPhoneLength,
+		"":                utils.ErrPhoneLength,
+	}
+	for entrada, esperado := range casos {
+		if _, err := utils.ParsePhoneBR(entrada); err != esperado {
+			t.Errorf("ParsePhoneBR(%q): esperado erro %v, obteve %v", entrada, esperado, err)
+		}
+	}
+}
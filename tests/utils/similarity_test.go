@@ -0,0 +1,62 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"minha-api/utils"
+)
+
+func TestNormalizeName(t *testing.T) {
+	casos := map[string]string{
+		"ACME Ltda":              "acme",
+		"Acme LTDA.":             "acme",
+		"Padaria São João ME":    "padaria sao joao",
+		"Construtora Alfa S/A":   "construtora alfa",
+		"Construtora Alfa S.A.":  "construtora alfa",
+		"Beta Comércio - EIRELI": "beta comercio",
+		"  José   da Silva  ":    "jose da silva",
+		"Mercado Ltda Ltda":      "mercado",
+		"Samambaia Flores":       "samambaia flores", // "sa" só é removido como palavra inteira
+	}
+	for entrada, esperado := range casos {
+		if got := utils.NormalizeName(entrada); got != esperado {
+			t.Errorf("NormalizeName(%q) = %q, esperado %q", entrada, got, esperado)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	casos := []struct {
+		a, b     string
+		esperado int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"joao", "joão", 1},
+		{"silva", "silva", 0},
+	}
+	for _, c := range casos {
+		if got := utils.Levenshtein(c.a, c.b); got != c.esperado {
+			t.Errorf("Levenshtein(%q, %q) = %d, esperado %d", c.a, c.b, got, c.esperado)
+		}
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	if got := utils.NameSimilarity("acme", "acme"); got != 1 {
+		t.Errorf("nomes iguais: nota %v, esperado 1", got)
+	}
+	// palavras fora de ordem: trigramas iguais
+	if got := utils.TrigramSimilarity("silva jose", "jose silva"); math.Abs(got-1) > 1e-9 {
+		t.Errorf("palavras trocadas: nota %v, esperado 1", got)
+	}
+	// erro de digitação em nome curto
+	if got := utils.NameSimilarity("mariana", "marina"); got < 0.8 {
+		t.Errorf("erro de digitação: nota %v, esperado >= 0.8", got)
+	}
+	if got := utils.NameSimilarity("acme", "zeta transportes"); got > 0.3 {
+		t.Errorf("nomes diferentes: nota %v, esperado <= 0.3", got)
+	}
+}
@@ -0,0 +1,240 @@
+package utils_test
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"minha-api/utils"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSelectSheets(t *testing.T) {
+	abas := []string{"Clientes", "Fornecedores", "2024"}
+	casos := []struct {
+		seletor  string
+		todas    bool
+		esperado []int
+	}{
+		{"", false, []int{0}},
+		{"fornecedores", false, []int{1}},
+		{" Clientes ", false, []int{0}},
+		{"2", false, []int{1}},
+		{"2024", false, []int{2}}, // nome tem prioridade sobre a posição
+		{"qualquer", true, []int{0, 1, 2}},
+	}
+	for _, c := range casos {
+		idx, err := utils.SelectSheets(abas, c.seletor, c.todas)
+		if err != nil {
+			t.Errorf("SelectSheets(%q, %v): erro inesperado: %v", c.seletor, c.todas, err)
+			continue
+		}
+		if !reflect.DeepEqual(idx, c.esperado) {
+			t.Errorf("SelectSheets(%q, %v) = %v, esperado %v", c.seletor, c.todas, idx, c.esperado)
+		}
+	}
+
+	for _, seletor := range []string{"0", "4", "Vendas"} {
+		if _, err := utils.SelectSheets(abas, seletor, false); err == nil {
+			t.Errorf("SelectSheets(%q): esperava erro", seletor)
+		}
+	}
+	if _, err := utils.SelectSheets(nil, "", false); err == nil {
+		t.Error("SelectSheets sem abas: esperava erro")
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	casos := []struct {
+		nome        string
+		entrada     []byte
+		texto       string
+		codificacao string
+	}{
+		{"utf-8", []byte("São Paulo"), "São Paulo", utils.EncodingUTF8},
+		{"utf-8 com BOM", append([]byte{0xEF, 0xBB, 0xBF}, "Ação"...), "Ação", utils.EncodingUTF8},
+		{"windows-1252", []byte{'S', 0xE3, 'o', ' ', 'J', 'o', 0xE3, 'o', ' ', 0x96, ' ', 'R', '$'}, "São João – R$", utils.EncodingCP1252},
+		{"utf-16 LE", []byte{0xFF, 0xFE, 'A', 0, 0xE7, 0, 0xE3, 0, 'o', 0}, "Ação", utils.EncodingUTF16},
+	}
+	for _, c := range casos {
+		texto, codificacao, err := utils.DecodeText(c.entrada)
+		if err != nil {
+			t.Errorf("%s: erro inesperado: %v", c.nome, err)
+			continue
+		}
+		if texto != c.texto || codificacao != c.codificacao {
+			t.Errorf("%s: obtido (%q, %s), esperado (%q, %s)", c.nome, texto, codificacao, c.texto, c.codificacao)
+		}
+	}
+}
+
+func TestDetectDelimiter(t *testing.T) {
+	casos := map[string]rune{
+		"Nome;Email;Telefone\nAna;ana@x.com;11": ';',
+		"Nome,Email,Telefone":                   ',',
+		"Nome\tEmail\tTelefone":                 '\t',
+		"Nome|Email|Telefone":                   '|',
+		"\n\n\"Silva, Ana\";Email;Telefone":     ';', // vírgula entre aspas não conta
+		"Nome":                                  ',',
+	}
+	for entrada, esperado := range casos {
+		if got := utils.DetectDelimiter(entrada); got != esperado {
+			t.Errorf("DetectDelimiter(%q) = %q, esperado %q", entrada, got, esperado)
+		}
+	}
+}
+
+func TestOpenSpreadsheetCSVWindows1252(t *testing.T) {
+	// Exportação típica de ERP: Windows-1252, separador ";" e campo com quebra de linha entre aspas
+	conteudo := []byte("Nome;Endere\xe7o\r\nJos\xe9;\"Rua A, 1\r\nCentro\"\r\n")
+	path := filepath.Join(t.TempDir(), "clientes.csv")
+	if err := os.WriteFile(path, conteudo, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	planilha, err := utils.OpenSpreadsheet(path, ".CSV")
+	if err != nil {
+		t.Fatalf("erro ao abrir CSV: %v", err)
+	}
+	defer planilha.Close()
+
+	it, err := planilha.Rows(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linhas, err := lerLinhas(it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperado := [][]string{{"Nome", "Endereço"}, {"José", "Rua A, 1\nCentro"}}
+	if !reflect.DeepEqual(linhas, esperado) {
+		t.Errorf("linhas = %q, esperado %q", linhas, esperado)
+	}
+	if len(planilha.SheetNames()) != 1 {
+		t.Errorf("CSV deve ter uma única aba, obtido %v", planilha.SheetNames())
+	}
+}
+
+func TestOpenSpreadsheetODS(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+  xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+  xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+ <office:body><office:spreadsheet>
+  <table:table table:name="Clientes">
+   <table:table-row>
+    <table:table-cell office:value-type="string"><text:p>Nome</text:p></table:table-cell>
+    <table:table-cell/>
+    <table:table-cell office:value-type="string"><text:p>Cidade</text:p></table:table-cell>
+    <table:table-cell table:number-columns-repeated="1020"/>
+   </table:table-row>
+   <table:table-row table:number-rows-repeated="2"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+   <table:table-row>
+    <table:table-cell office:value-type="string"><text:p>Ana<text:s text:c="2"/>Maria</text:p></table:table-cell>
+    <table:table-cell office:value-type="float" office:value="42"><text:p>42</text:p></table:table-cell>
+    <table:table-cell office:value-type="string"><text:p>São Paulo</text:p></table:table-cell>
+   </table:table-row>
+   <table:table-row table:number-rows-repeated="1048572"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+  </table:table>
+  <table:table table:name="Vazia"/>
+ </office:spreadsheet></office:body>
+</office:document-content>`
+	path := filepath.Join(t.TempDir(), "clientes.ods")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := zip.NewWriter(f)
+	w, err := z.Create("content.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(content))
+	z.Close()
+	f.Close()
+
+	planilha, err := utils.OpenSpreadsheet(path, ".ods")
+	if err != nil {
+		t.Fatalf("erro ao abrir ODS: %v", err)
+	}
+	defer planilha.Close()
+
+	if nomes := planilha.SheetNames(); !reflect.DeepEqual(nomes, []string{"Clientes", "Vazia"}) {
+		t.Errorf("abas = %v", nomes)
+	}
+	it, err := planilha.Rows(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linhas, err := lerLinhas(it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperado := [][]string{{"Nome", "", "Cidade"}, nil, nil, {"Ana  Maria", "42", "São Paulo"}}
+	if !reflect.DeepEqual(linhas, esperado) {
+		t.Errorf("linhas = %q, esperado %q", linhas, esperado)
+	}
+	if _, err := planilha.Rows(2); err == nil {
+		t.Error("esperava erro para aba inexistente")
+	}
+}
+
+func TestOpenSpreadsheetXLSXStreaming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clientes.xlsx")
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const total = 5000
+	sw.SetRow("A1", []interface{}{"Nome", "Email"})
+	for i := 2; i <= total+1; i++ {
+		sw.SetRow(fmt.Sprintf("A%d", i), []interface{}{fmt.Sprintf("Cliente %d", i), fmt.Sprintf("c%d@x.com", i)})
+	}
+	if err := sw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+
+	planilha, err := utils.OpenSpreadsheet(path, ".xlsx")
+	if err != nil {
+		t.Fatalf("erro ao abrir XLSX: %v", err)
+	}
+	defer planilha.Close()
+	it, err := planilha.Rows(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	count := 0
+	var ultima []string
+	for it.Next() {
+		count++
+		ultima = it.Row()
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != total+1 || !reflect.DeepEqual(ultima, []string{fmt.Sprintf("Cliente %d", total+1), fmt.Sprintf("c%d@x.com", total+1)}) {
+		t.Errorf("lidas %d linhas, última %q", count, ultima)
+	}
+	if _, err := planilha.Rows(1); err == nil {
+		t.Error("esperava erro para aba inexistente")
+	}
+}
+
+// lerLinhas consome o iterador e retorna todas as linhas
+func lerLinhas(it utils.RowIterator) ([][]string, error) {
+	defer it.Close()
+	var rows [][]string
+	for it.Next() {
+		rows = append(rows, it.Row())
+	}
+	return rows, it.Err()
+}
@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"testing"
+
+	"minha-api/utils"
+)
+
+func TestRemoveAccents(t *testing.T) {
+	casos := map[string]string{
+		"Endereço":     "Endereco",
+		"Razão Social": "Razao Social",
+		"E-mail":       "E-mail",
+		"ÁÉÍÓÚ àèìòù":  "AEIOU aeiou",
+	}
+	for entrada, esperado := range casos {
+		if got := utils.RemoveAccents(entrada); got != esperado {
+			t.Errorf("RemoveAccents(%q): esperado %q, obteve %q", entrada, esperado, got)
+		}
+	}
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	casos := map[string]string{
+		"Ana Silv":             "ana:* & silv:*",
+		"  JOÃO  ":             "joao:*",
+		"acme@x.com":           "acme:* & x:* & com:*",
+		"o'brien & cia | !(x)": "o:* & brien:* & cia:* & x:*", // operadores do tsquery são descartados
+		"11.222.333/0001-81":   "11:* & 222:* & 333:* & 0001:* & 81:*",
+		"  ;;  ":               "",
+	}
+	for entrada, esperado := range casos {
+		if got := utils.PrefixTSQuery(entrada); got != esperado {
+			t.Errorf("PrefixTSQuery(%q) = %q, esperado %q", entrada, got, esperado)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got := utils.EscapeLike(`100%_a\b`); got != `100\%\_a\\b` {
+		t.Errorf("EscapeLike = %q", got)
+	}
+}
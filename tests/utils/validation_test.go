@@ -0,0 +1,54 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"minha-api/utils"
+)
+
+func TestIsValidEmail(t *testing.T) {
+	validos := []string{"fulano@empresa.com.br", "a.b+c@x.io", "nome_sobrenome%x@dominio.com"}
+	invalidos := []string{"", "fulano", "fulano@", "@empresa.com", "fulano@empresa", "ful ano@empresa.com"}
+	for _, e := range validos {
+		if !utils.IsValidEmail(e) {
+			t.Errorf("IsValidEmail(%q): esperado válido", e)
+		}
+	}
+	for _, e := range invalidos {
+		if utils.IsValidEmail(e) {
+			t.Errorf("IsValidEmail(%q): esperado inválido", e)
+		}
+	}
+}
+
+func TestValidator(t *testing.T) {
+	v := &utils.Validator{}
+	v.Required("name", "  ")
+	v.MaxLength("address", strings.Repeat("x", 11), 10)
+	v.Email("email", "invalido")
+	v.Phone("phone", "(11) 9876")
+	v.Document("document", "123")
+	v.Email("email2", "") // opcional
+
+	errs := v.Errors()
+	esperados := []utils.FieldError{
+		{Field: "name", Code: utils.CodeRequired},
+		{Field: "address", Code: utils.CodeTooLong},
+		{Field: "email", Code: utils.CodeInvalidEmail},
+		{Field: "phone", Code: utils.CodeInvalidPhone},
+		{Field: "document", Code: utils.CodeInvalidDocument},
+	}
+	if len(errs) != len(esperados) {
+		t.Fatalf("esperado %d erros, obteve %d: %v", len(esperados), len(errs), errs)
+	}
+	for i, e := range esperados {
+		if errs[i].Field != e.Field || errs[i].Code != e.Code || errs[i].Message == "" {
+			t.Errorf("erro %d: esperado %s/%s, obteve %+v", i, e.Field, e.Code, errs[i])
+		}
+	}
+
+	if (&utils.Validator{}).Errors() != nil {
+		t.Errorf("validador sem erros deveria retornar nil")
+	}
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// validUFs são as siglas das 27 unidades federativas
+var validUFs = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true, "ES": true, "GO": true,
+	"MA": true, "MT": true, "MS": true, "MG": true, "PA": true, "PB": true, "PR": true, "PE": true, "PI": true,
+	"RJ": true, "RN": true, "RS": true, "RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+var cepRegex = regexp.MustCompile(`^\d{5}-?\d{3}$`)
+
+// IsValidUF indica se a sigla (em qualquer caixa) é de uma UF brasileira
+func IsValidUF(uf string) bool {
+	return validUFs[strings.ToUpper(strings.TrimSpace(uf))]
+}
+
+// IsValidCEP aceita CEPs no formato 00000-000 ou 00000000
+func IsValidCEP(cep string) bool {
+	return cepRegex.MatchString(strings.TrimSpace(cep))
+}
+
+// FormatCEP aplica a máscara 00000-000 a um CEP com 8 dígitos
+func FormatCEP(cep string) string {
+	digits := OnlyDigits(cep)
+	if len(digits) != 8 {
+		return cep
+	}
+	return digits[:5] + "-" + digits[5:]
+}
@@ -0,0 +1,306 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCompanyNotFound indica um CNPJ que o cadastro de empresas não conhece
+var ErrCompanyNotFound = errors.New("CNPJ não encontrado no cadastro de empresas")
+
+// ErrRateLimited indica que o limite de consultas foi atingido e a consulta não foi feita
+var ErrRateLimited = errors.New("limite de consultas atingido, tente novamente mais tarde")
+
+// CompanyInfo são os dados públicos de uma empresa no cadastro da Receita Federal
+type CompanyInfo struct {
+	CNPJ         string `json:"cnpj"` // somente dígitos
+	LegalName    string `json:"legal_name"`
+	TradeName    string `json:"trade_name,omitempty"`
+	Status       string `json:"status,omitempty"` // situação cadastral, ex.: ATIVA
+	Email        string `json:"email,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	Street       string `json:"street,omitempty"`
+	Number       string `json:"number,omitempty"`
+	Complement   string `json:"complement,omitempty"`
+	Neighborhood string `json:"neighborhood,omitempty"`
+	City         string `json:"city,omitempty"`
+	UF           string `json:"uf,omitempty"`
+	CEP          string `json:"cep,omitempty"`
+}
+
+// CompanyRegistry consulta os dados de uma empresa pelo CNPJ (somente dígitos)
+type CompanyRegistry interface {
+	LookupCNPJ(ctx context.Context, cnpj string) (*CompanyInfo, error)
+}
+
+// Padrões da configuração do cadastro de empresas (ver NewCompanyRegistryFromEnv)
+const (
+	defaultCompanyRegistryURL      = "https://brasilapi.com.br/api/cnpj/v1"
+	defaultCompanyRegistryRate     = 30 // consultas por minuto
+	defaultCompanyRegistryCacheTTL = 7 * 24 * time.Hour
+)
+
+// NewCompanyRegistryFromEnv cria o cadastro de empresas HTTP com cache a partir de COMPANY_REGISTRY_URL
+// (padrão BrasilAPI), COMPANY_REGISTRY_RATE (consultas por minuto, padrão 30) e COMPANY_REGISTRY_CACHE_TTL
+// (ex.: "24h", padrão 7 dias)
+func NewCompanyRegistryFromEnv() CompanyRegistry {
+	baseURL := strings.TrimSpace(os.Getenv("COMPANY_REGISTRY_URL"))
+	if baseURL == "" {
+		baseURL = defaultCompanyRegistryURL
+	}
+	rate := defaultCompanyRegistryRate
+	if s := os.Getenv("COMPANY_REGISTRY_RATE"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			rate = n
+		} else {
+			fmt.Printf("[AVISO] COMPANY_REGISTRY_RATE inválido (%q), usando %d\n", s, defaultCompanyRegistryRate)
+		}
+	}
+	ttl := defaultCompanyRegistryCacheTTL
+	if s := os.Getenv("COMPANY_REGISTRY_CACHE_TTL"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			ttl = d
+		} else {
+			fmt.Printf("[AVISO] COMPANY_REGISTRY_CACHE_TTL inválido (%q), usando %s\n", s, defaultCompanyRegistryCacheTTL)
+		}
+	}
+	return NewCachedCompanyRegistry(NewHTTPCompanyRegistry(baseURL, rate), ttl)
+}
+
+// HTTPCompanyRegistry consulta uma API JSON no formato da BrasilAPI (/api/cnpj/v1/{cnpj}) ou da ReceitaWS
+// (/v1/cnpj/{cnpj}): GET {BaseURL}/{cnpj}. As consultas são espaçadas para respeitar o limite da API.
+type HTTPCompanyRegistry struct {
+	BaseURL string
+	Client  *http.Client
+	limiter *RateLimiter
+}
+
+// NewHTTPCompanyRegistry cria o cliente da API com no máximo perMinute consultas por minuto
+func NewHTTPCompanyRegistry(baseURL string, perMinute int) *HTTPCompanyRegistry {
+	return &HTTPCompanyRegistry{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Client:  &http.Client{Timeout: 15 * time.Second},
+		limiter: NewRateLimiter(perMinute),
+	}
+}
+
+// companyRegistryResponse aceita os campos da BrasilAPI e da ReceitaWS
+type companyRegistryResponse struct {
+	CNPJ string `json:"cnpj"`
+	// BrasilAPI
+	RazaoSocial                string `json:"razao_social"`
+	NomeFantasia               string `json:"nome_fantasia"`
+	DescricaoSituacaoCadastral string `json:"descricao_situacao_cadastral"`
+	DDDTelefone1               string `json:"ddd_telefone_1"`
+	TipoLogradouro             string `json:"descricao_tipo_de_logradouro"`
+	// ReceitaWS
+	Nome     string `json:"nome"`
+	Fantasia string `json:"fantasia"`
+	Situacao string `json:"situacao"`
+	Telefone string `json:"telefone"`
+	Status   string `json:"status"` // OK ou ERROR
+	Message  string `json:"message"`
+	// Comuns
+	Email       string `json:"email"`
+	Logradouro  string `json:"logradouro"`
+	Numero      string `json:"numero"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Municipio   string `json:"municipio"`
+	UF          string `json:"uf"`
+	CEP         string `json:"cep"`
+}
+
+func (r *HTTPCompanyRegistry) LookupCNPJ(ctx context.Context, cnpj string) (*CompanyInfo, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.BaseURL+"/"+OnlyDigits(cnpj), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar o cadastro de empresas: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// A API avisa quando podemos voltar; até lá nenhuma consulta é enviada
+		wait := time.Minute
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+			wait = time.Duration(s) * time.Second
+		}
+		r.limiter.PauseUntil(time.Now().Add(wait))
+		return nil, ErrRateLimited
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest:
+		return nil, ErrCompanyNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("cadastro de empresas respondeu %d", resp.StatusCode)
+	}
+	var body companyRegistryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("resposta inválida do cadastro de empresas: %w", err)
+	}
+	if strings.EqualFold(body.Status, "ERROR") {
+		return nil, ErrCompanyNotFound // a ReceitaWS responde 200 com status ERROR para CNPJs inválidos ou desconhecidos
+	}
+	info := body.companyInfo()
+	if info.CNPJ == "" {
+		info.CNPJ = OnlyDigits(cnpj)
+	}
+	return info, nil
+}
+
+// companyInfo converte a resposta, usando os campos da BrasilAPI ou, na falta deles, os da ReceitaWS
+func (b companyRegistryResponse) companyInfo() *CompanyInfo {
+	first := func(values ...string) string {
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	street := strings.TrimSpace(b.Logradouro)
+	if t := strings.TrimSpace(b.TipoLogradouro); t != "" && !strings.HasPrefix(strings.ToUpper(street), strings.ToUpper(t)+" ") {
+		street = strings.TrimSpace(t + " " + street) // a BrasilAPI separa o tipo (RUA, AVENIDA) do nome
+	}
+	phone := first(b.DDDTelefone1, b.Telefone)
+	if i := strings.Index(phone, "/"); i >= 0 {
+		phone = strings.TrimSpace(phone[:i]) // a ReceitaWS junta os telefones: "(11) 1234-5678 / (11) 8765-4321"
+	}
+	return &CompanyInfo{
+		CNPJ:         OnlyDigits(b.CNPJ),
+		LegalName:    first(b.RazaoSocial, b.Nome),
+		TradeName:    first(b.NomeFantasia, b.Fantasia),
+		Status:       first(b.DescricaoSituacaoCadastral, b.Situacao),
+		Email:        strings.ToLower(strings.TrimSpace(b.Email)),
+		Phone:        phone,
+		Street:       street,
+		Number:       strings.TrimSpace(b.Numero),
+		Complement:   strings.TrimSpace(b.Complemento),
+		Neighborhood: strings.TrimSpace(b.Bairro),
+		City:         strings.TrimSpace(b.Municipio),
+		UF:           strings.ToUpper(strings.TrimSpace(b.UF)),
+		CEP:          OnlyDigits(b.CEP),
+	}
+}
+
+// CachedCompanyRegistry guarda por ttl as respostas de outro CompanyRegistry, inclusive os CNPJs não
+// encontrados. Falhas e limites de consulta não são guardados.
+type CachedCompanyRegistry struct {
+	next    CompanyRegistry
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]companyCacheEntry
+}
+
+type companyCacheEntry struct {
+	info      *CompanyInfo // nil: CNPJ não encontrado
+	expiresAt time.Time
+}
+
+// companyCacheMaxEntries é o tamanho a partir do qual as entradas expiradas são descartadas
+const companyCacheMaxEntries = 10000
+
+func NewCachedCompanyRegistry(next CompanyRegistry, ttl time.Duration) *CachedCompanyRegistry {
+	return &CachedCompanyRegistry{next: next, ttl: ttl, entries: map[string]companyCacheEntry{}}
+}
+
+func (c *CachedCompanyRegistry) LookupCNPJ(ctx context.Context, cnpj string) (*CompanyInfo, error) {
+	key := OnlyDigits(cnpj)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		if entry.info == nil {
+			return nil, ErrCompanyNotFound
+		}
+		info := *entry.info
+		return &info, nil
+	}
+
+	info, err := c.next.LookupCNPJ(ctx, key)
+	if err != nil && !errors.Is(err, ErrCompanyNotFound) {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= companyCacheMaxEntries {
+		now := time.Now()
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	var cached *CompanyInfo
+	if info != nil {
+		copied := *info
+		cached = &copied
+	}
+	c.entries[key] = companyCacheEntry{info: cached, expiresAt: time.Now().Add(c.ttl)}
+	return info, err
+}
+
+// RateLimiter espaça chamadas para no máximo perMinute por minuto
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // próximo horário livre
+}
+
+func NewRateLimiter(perMinute int) *RateLimiter {
+	if perMinute <= 0 {
+		perMinute = 1
+	}
+	return &RateLimiter{interval: time.Minute / time.Duration(perMinute)}
+}
+
+// Wait reserva o próximo horário livre e espera por ele. Se esse horário for depois do prazo de ctx,
+// não espera nem reserva: retorna ErrRateLimited na hora.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	if deadline, ok := ctx.Deadline(); ok && at.After(deadline) {
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if d := time.Until(at); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
+
+// PauseUntil impede novas chamadas antes de t (ex.: Retry-After de uma resposta 429)
+func (l *RateLimiter) PauseUntil(t time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t.After(l.next) {
+		l.next = t
+	}
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// Tipos de documento e de pessoa aceitos para clientes
+const (
+	DocumentTypeCPF  = "cpf"
+	DocumentTypeCNPJ = "cnpj"
+
+	PersonTypeIndividual = "PF" // pessoa física (CPF)
+	PersonTypeCompany    = "PJ" // pessoa jurídica (CNPJ)
+)
+
+var (
+	ErrDocumentLength     = errors.New("documento deve ter 11 dígitos (CPF) ou 14 dígitos (CNPJ)")
+	ErrDocumentCheckDigit = errors.New("dígito verificador do documento inválido")
+	ErrPersonType         = errors.New("tipo de pessoa deve ser PF ou PJ")
+	ErrPersonTypeMismatch = errors.New("tipo de pessoa não corresponde ao documento (CPF = PF, CNPJ = PJ)")
+)
+
+// Document representa um CPF ou CNPJ já validado, armazenado apenas com dígitos
+type Document struct {
+	Type   string
+	Digits string
+}
+
+// ParseDocument aceita CPF ou CNPJ com ou sem máscara e valida os dígitos verificadores (módulo 11)
+func ParseDocument(raw string) (Document, error) {
+	digits := OnlyDigits(raw)
+	switch len(digits) {
+	case 11:
+		if !IsValidCPF(digits) {
+			return Document{}, ErrDocumentCheckDigit
+		}
+		return Document{Type: DocumentTypeCPF, Digits: digits}, nil
+	case 14:
+		if !IsValidCNPJ(digits) {
+			return Document{}, ErrDocumentCheckDigit
+		}
+		return Document{Type: DocumentTypeCNPJ, Digits: digits}, nil
+	}
+	return Document{}, ErrDocumentLength
+}
+
+// PersonType retorna PF para CPF e PJ para CNPJ
+func (d Document) PersonType() string {
+	if d.Type == DocumentTypeCNPJ {
+		return PersonTypeCompany
+	}
+	return PersonTypeIndividual
+}
+
+// Formatted retorna o documento com a máscara usual (000.000.000-00 ou 00.000.000/0000-00)
+func (d Document) Formatted() string {
+	return FormatDocument(d.Digits)
+}
+
+// FormatDocument aplica a máscara de CPF ou CNPJ conforme a quantidade de dígitos.
+// Valores com outra quantidade de dígitos são devolvidos sem alteração.
+func FormatDocument(digits string) string {
+	switch len(digits) {
+	case 11:
+		return digits[0:3] + "." + digits[3:6] + "." + digits[6:9] + "-" + digits[9:11]
+	case 14:
+		return digits[0:2] + "." + digits[2:5] + "." + digits[5:8] + "/" + digits[8:12] + "-" + digits[12:14]
+	}
+	return digits
+}
+
+// IsValidCPF valida os dois dígitos verificadores de um CPF com 11 dígitos
+func IsValidCPF(digits string) bool {
+	if len(digits) != 11 || allSameDigit(digits) {
+		return false
+	}
+	return mod11Digit(digits[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[9] &&
+		mod11Digit(digits[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[10]
+}
+
+// IsValidCNPJ valida os dois dígitos verificadores de um CNPJ com 14 dígitos
+func IsValidCNPJ(digits string) bool {
+	if len(digits) != 14 || allSameDigit(digits) {
+		return false
+	}
+	return mod11Digit(digits[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[12] &&
+		mod11Digit(digits[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[13]
+}
+
+// OnlyDigits remove tudo que não for dígito (pontos, traços, barras, espaços)
+func OnlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mod11Digit calcula um dígito verificador pelo módulo 11 com os pesos informados
+func mod11Digit(digits string, weights []int) byte {
+	sum := 0
+	for i, w := range weights {
+		sum += int(digits[i]-'0') * w
+	}
+	rest := sum % 11
+	if rest < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - rest)
+}
+
+// allSameDigit identifica sequências como 000.000.000-00, que passam no módulo 11 mas não são válidas
+func allSameDigit(digits string) bool {
+	return strings.Count(digits, digits[:1]) == len(digits)
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Formatos de exportação aceitos por NewExporter
+const (
+	ExportCSV   = "csv"
+	ExportJSONL = "jsonl"
+	ExportXLSX  = "xlsx"
+)
+
+// ExportFormat descreve um formato de exportação: extensão do arquivo e Content-Type da resposta
+type ExportFormat struct {
+	Name        string
+	Extension   string
+	ContentType string
+}
+
+// ExportFormats lista os formatos suportados, na ordem de preferência quando o Accept aceita qualquer um
+var ExportFormats = []ExportFormat{
+	{ExportXLSX, ".xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	{ExportCSV, ".csv", "text/csv"},
+	{ExportJSONL, ".jsonl", "application/x-ndjson"},
+}
+
+// acceptAliases são outros Content-Types que o Accept pode usar para pedir um formato
+var acceptAliases = map[string]string{
+	"application/jsonl":     ExportJSONL,
+	"application/jsonlines": ExportJSONL,
+	"application/csv":       ExportCSV,
+}
+
+// LookupExportFormat busca o formato pelo nome (sem diferenciar maiúsculas)
+func LookupExportFormat(name string) (ExportFormat, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, f := range ExportFormats {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return ExportFormat{}, false
+}
+
+// ExportFormatFromAccept escolhe o formato a partir do cabeçalho Accept, na ordem em que os tipos aparecem.
+// Retorna false se nenhum tipo do cabeçalho for suportado.
+func ExportFormatFromAccept(accept string) (ExportFormat, bool) {
+	for _, part := range strings.Split(accept, ",") {
+		mime := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		if mime == "*/*" {
+			return ExportFormats[0], true
+		}
+		if name, ok := acceptAliases[mime]; ok {
+			return LookupExportFormat(name)
+		}
+		for _, f := range ExportFormats {
+			if f.ContentType == mime {
+				return f, true
+			}
+		}
+	}
+	return ExportFormat{}, false
+}
+
+// ExportColumn é uma coluna do arquivo exportado. Key é usada como chave no JSON Lines e Header como
+// cabeçalho no CSV e no XLSX.
+type ExportColumn struct {
+	Key    string
+	Header string
+}
+
+// ExportOptions ajusta a saída dos formatos que têm variações
+type ExportOptions struct {
+	Delimiter rune   // separador do CSV; zero usa ';'
+	BOM       bool   // grava o BOM UTF-8 no início do CSV, para o Excel reconhecer os acentos
+	SheetName string // nome da aba no XLSX; vazio usa "Dados"
+}
+
+// Exporter grava linhas de texto em um formato de arquivo. O cabeçalho é gravado por NewExporter;
+// Close finaliza o arquivo e deve ser chamado mesmo em caso de erro.
+type Exporter interface {
+	WriteRow(values []string) error
+	Close() error
+}
+
+// NewExporter cria o exportador do formato informado, gravando em w o cabeçalho com as colunas.
+// Cada linha passada a WriteRow deve ter um valor por coluna, na mesma ordem.
+func NewExporter(format string, w io.Writer, columns []ExportColumn, opts ExportOptions) (Exporter, error) {
+	switch format {
+	case ExportCSV:
+		return newCSVExporter(w, columns, opts)
+	case ExportJSONL:
+		return &jsonlExporter{w: bufio.NewWriter(w), columns: columns}, nil
+	case ExportXLSX:
+		return newXLSXExporter(w, columns, opts)
+	}
+	return nil, fmt.Errorf("formato de exportação não suportado: %s", format)
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func newCSVExporter(w io.Writer, columns []ExportColumn, opts ExportOptions) (*csvExporter, error) {
+	if opts.BOM {
+		if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+			return nil, err
+		}
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	if opts.Delimiter != 0 {
+		cw.Comma = opts.Delimiter
+	}
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Header
+	}
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvExporter{w: cw}, nil
+}
+
+func (e *csvExporter) WriteRow(values []string) error {
+	return e.w.Write(values)
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonlExporter grava um objeto JSON por linha, com as chaves na ordem das colunas
+type jsonlExporter struct {
+	w       *bufio.Writer
+	columns []ExportColumn
+}
+
+func (e *jsonlExporter) WriteRow(values []string) error {
+	e.w.WriteByte('{')
+	for i, col := range e.columns {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(col.Key)
+		value, _ := json.Marshal(values[i])
+		e.w.Write(key)
+		e.w.WriteByte(':')
+		e.w.Write(value)
+	}
+	_, err := e.w.WriteString("}\n")
+	return err
+}
+
+func (e *jsonlExporter) Close() error {
+	return e.w.Flush()
+}
+
+// xlsxExporter grava as linhas com o StreamWriter do excelize, que mantém em memória só a linha corrente;
+// o arquivo é montado e gravado em w no Close.
+type xlsxExporter struct {
+	w    io.Writer
+	f    *excelize.File
+	sw   *excelize.StreamWriter
+	next int
+}
+
+func newXLSXExporter(w io.Writer, columns []ExportColumn, opts ExportOptions) (*xlsxExporter, error) {
+	sheet := opts.SheetName
+	if sheet == "" {
+		sheet = "Dados"
+	}
+	f := excelize.NewFile()
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	e := &xlsxExporter{w: w, f: f, sw: sw, next: 1}
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Header
+	}
+	if err := e.WriteRow(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *xlsxExporter) WriteRow(values []string) error {
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	cell, _ := excelize.CoordinatesToCellName(1, e.next)
+	e.next++
+	return e.sw.SetRow(cell, row)
+}
+
+func (e *xlsxExporter) Close() error {
+	defer e.f.Close()
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := e.f.WriteTo(e.w)
+	return err
+}
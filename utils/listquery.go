@@ -0,0 +1,326 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tipos de campo aceitos em filtros e ordenação de listagens
+const (
+	ListString = "string"
+	ListNumber = "number"
+	ListTime   = "time"
+)
+
+// Operadores de filtro: ?campo=valor (eq), ?campo[ilike]=trecho, ?campo[in]=a,b, ?campo[gte]=x, ?campo[lte]=x
+const (
+	OpEq    = "eq"
+	OpILike = "ilike" // contém o trecho, sem diferenciar maiúsculas
+	OpIn    = "in"
+	OpGte   = "gte"
+	OpLte   = "lte"
+)
+
+// Parâmetros reservados da linguagem de listagem
+const (
+	ListParamSort   = "sort"
+	ListParamLimit  = "limit"
+	ListParamOffset = "offset"
+	ListParamCursor = "cursor"
+)
+
+var listOperators = map[string][]string{
+	ListString: {OpEq, OpILike, OpIn, OpGte, OpLte},
+	ListNumber: {OpEq, OpIn, OpGte, OpLte},
+	ListTime:   {OpEq, OpGte, OpLte},
+}
+
+// ListField é um campo liberado para filtro e ordenação em uma listagem
+type ListField struct {
+	Column string // coluna no banco
+	Type   string // ListString, ListNumber ou ListTime
+}
+
+// ListSpec é a lista branca de campos de uma entidade e os padrões da listagem
+type ListSpec struct {
+	Fields       map[string]ListField // nome usado na API -> campo
+	DefaultSort  string               // no formato de ?sort=, ex.: "-created_at"
+	KeyField     string               // campo único usado para desempatar a ordenação; deve estar em Fields
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// ListFilter é um filtro já validado, com os valores convertidos para o tipo do campo
+type ListFilter struct {
+	Field  string
+	Column string
+	Op     string
+	Values []interface{}
+}
+
+// ListSort é um critério de ordenação
+type ListSort struct {
+	Field  string
+	Column string
+	Type   string
+	Desc   bool
+}
+
+// listNullValues é o valor que substitui NULL em cada tipo de campo ao ordenar e paginar por cursor,
+// para linhas com a coluna vazia não sumirem das páginas (NULL não é maior nem menor que nada)
+var listNullValues = map[string]string{
+	ListString: "''",
+	ListNumber: "0",
+	ListTime:   "'0001-01-01T00:00:00Z'",
+}
+
+// Expr é a expressão SQL usada para ordenar e comparar pela coluna: NULL vira o valor zero do tipo
+// (”, 0 ou 0001-01-01), o mesmo gravado no cursor para itens com a coluna vazia
+func (s ListSort) Expr() string {
+	zero, ok := listNullValues[s.Type]
+	if !ok {
+		return s.Column
+	}
+	return "COALESCE(" + s.Column + ", " + zero + ")"
+}
+
+// ListQuery é uma consulta de listagem já validada contra a ListSpec
+type ListQuery struct {
+	Filters []ListFilter
+	Sort    []ListSort // sempre termina no KeyField, para a ordem ser estável
+	Limit   int
+	Offset  int
+	// After são os valores das colunas de Sort do último item da página anterior (paginação por cursor)
+	After []interface{}
+}
+
+// KeysetCondition monta a condição SQL "vem depois de After" na ordenação Sort, para a paginação por cursor:
+// (a > x) OR (a = x AND b > y) OR ..., com < nas colunas em ordem decrescente. As colunas entram pela
+// expressão de ListSort.Expr, a mesma do ORDER BY, para linhas com NULL não ficarem fora das páginas.
+func (q ListQuery) KeysetCondition() (string, []interface{}) {
+	var ors []string
+	var args []interface{}
+	for i, s := range q.Sort {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, q.Sort[j].Expr()+" = ?")
+			args = append(args, q.After[j])
+		}
+		op := " > ?"
+		if s.Desc {
+			op = " < ?"
+		}
+		ands = append(ands, s.Expr()+op)
+		args = append(args, q.After[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// listParamRegex separa "campo[operador]"
+var listParamRegex = regexp.MustCompile(`^([a-zA-Z0-9_.]+)\[([a-z]+)\]$`)
+
+// ParseListQuery lê filtros, ordenação e paginação da query string. Parâmetros sem operador que não estão na
+// lista branca são ignorados (podem ser filtros próprios do endpoint); com operador, geram erro.
+func ParseListQuery(q url.Values, spec ListSpec) (ListQuery, ValidationErrors) {
+	var v Validator
+	lq := ListQuery{Limit: spec.DefaultLimit}
+
+	keys := make([]string, 0, len(q))
+	for key := range q {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys) // erros em ordem estável
+	for _, key := range keys {
+		name, op := key, OpEq
+		if m := listParamRegex.FindStringSubmatch(key); m != nil {
+			name, op = m[1], m[2]
+		} else if slices.Contains([]string{ListParamSort, ListParamLimit, ListParamOffset, ListParamCursor}, key) {
+			continue
+		}
+		field, ok := spec.Fields[name]
+		if !ok {
+			if op != OpEq || key != name {
+				v.Add(key, CodeInvalidValue, "Campo não pode ser filtrado")
+			}
+			continue
+		}
+		if !slices.Contains(listOperators[field.Type], op) {
+			v.Add(key, CodeInvalidValue, fmt.Sprintf("Operador não aceito para o campo. Use: %s", strings.Join(listOperators[field.Type], ", ")))
+			continue
+		}
+		raw := q.Get(key)
+		items := []string{raw}
+		if op == OpIn {
+			items = strings.Split(raw, ",")
+		}
+		filter := ListFilter{Field: name, Column: field.Column, Op: op}
+		for _, item := range items {
+			value, err := parseListValue(field.Type, strings.TrimSpace(item))
+			if err != nil {
+				v.Add(key, CodeInvalidValue, err.Error())
+				break
+			}
+			filter.Values = append(filter.Values, value)
+		}
+		lq.Filters = append(lq.Filters, filter)
+	}
+
+	sortParam := q.Get(ListParamSort)
+	if sortParam == "" {
+		sortParam = spec.DefaultSort
+	}
+	lq.Sort = parseListSort(sortParam, spec, &v)
+
+	if s := q.Get(ListParamLimit); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > spec.MaxLimit {
+			v.Add(ListParamLimit, CodeInvalidValue, fmt.Sprintf("Use um inteiro entre 1 e %d", spec.MaxLimit))
+		}
+		lq.Limit = n
+	}
+	if s := q.Get(ListParamOffset); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			v.Add(ListParamOffset, CodeInvalidValue, "Use um inteiro maior ou igual a zero")
+		}
+		lq.Offset = n
+	}
+	if s := q.Get(ListParamCursor); s != "" {
+		if lq.Offset != 0 {
+			v.Add(ListParamCursor, CodeInvalidValue, "Use cursor ou offset, não os dois")
+		} else if after, err := decodeListCursor(s, lq.Sort); err != nil {
+			v.Add(ListParamCursor, CodeInvalidValue, err.Error())
+		} else {
+			lq.After = after
+		}
+	}
+	return lq, v.Errors()
+}
+
+// parseListSort lê "campo1,-campo2" (o "-" inverte a ordem) e acrescenta o KeyField como desempate
+func parseListSort(s string, spec ListSpec, v *Validator) []ListSort {
+	var sorts []ListSort
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(part, "-")
+		field, ok := spec.Fields[name]
+		if !ok {
+			v.Add(ListParamSort, CodeInvalidValue, fmt.Sprintf("Campo não pode ser ordenado: %s", name))
+			continue
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		sorts = append(sorts, ListSort{Field: name, Column: field.Column, Type: field.Type, Desc: desc})
+	}
+	if !seen[spec.KeyField] {
+		key := spec.Fields[spec.KeyField]
+		sorts = append(sorts, ListSort{Field: spec.KeyField, Column: key.Column, Type: key.Type})
+	}
+	return sorts
+}
+
+func parseListValue(typ, s string) (interface{}, error) {
+	switch typ {
+	case ListNumber:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Valor numérico inválido: %s", s)
+		}
+		return n, nil
+	case ListTime:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("Data inválida: %s. Use AAAA-MM-DD ou RFC 3339", s)
+	}
+	return s, nil
+}
+
+// listCursor é o conteúdo do cursor de paginação: a ordenação usada e os valores do último item
+type listCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+// EncodeListCursor gera o cursor da próxima página a partir dos valores das colunas de Sort do último item
+func EncodeListCursor(sorts []ListSort, values []interface{}) string {
+	c := listCursor{Sort: listSortString(sorts)}
+	for i, value := range values {
+		c.Values = append(c.Values, listCursorValue(sorts[i].Type, value))
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// listCursorValue converte o valor de uma coluna em texto para o cursor. Ponteiros são seguidos; nil (coluna
+// NULL) vira o valor zero do tipo, como em ListSort.Expr.
+func listCursorValue(typ string, value interface{}) string {
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			value = nil
+		} else {
+			value = rv.Elem().Interface()
+		}
+	}
+	if value == nil {
+		switch typ {
+		case ListNumber:
+			value = 0
+		case ListTime:
+			value = time.Time{}
+		default:
+			value = ""
+		}
+	}
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+func decodeListCursor(s string, sorts []ListSort) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	var c listCursor
+	if err != nil || json.Unmarshal(b, &c) != nil || len(c.Values) != len(sorts) {
+		return nil, fmt.Errorf("Cursor inválido")
+	}
+	if c.Sort != listSortString(sorts) {
+		return nil, fmt.Errorf("Cursor gerado para outra ordenação: mantenha o mesmo sort entre as páginas")
+	}
+	values := make([]interface{}, len(sorts))
+	for i, sort := range sorts {
+		if values[i], err = parseListValue(sort.Type, c.Values[i]); err != nil {
+			return nil, fmt.Errorf("Cursor inválido")
+		}
+	}
+	return values, nil
+}
+
+func listSortString(sorts []ListSort) string {
+	parts := make([]string, len(sorts))
+	for i, s := range sorts {
+		parts[i] = s.Field
+		if s.Desc {
+			parts[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
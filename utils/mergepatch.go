@@ -0,0 +1,27 @@
+package utils
+
+// MergePatch aplica um JSON Merge Patch (RFC 7396) a um documento JSON já decodificado (map[string]interface{},
+// []interface{}, string, float64, bool ou nil). Membros do patch com valor null removem o membro do documento;
+// objetos são mesclados recursivamente e qualquer outro valor substitui o atual. target não é alterado.
+func MergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	out := make(map[string]interface{}, len(t))
+	for k, v := range t {
+		out[k] = v
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		out[k] = MergePatch(out[k], v)
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// Classificação de telefones brasileiros
+const (
+	PhoneTypeMobile   = "mobile"
+	PhoneTypeLandline = "landline"
+)
+
+var (
+	ErrPhoneLength = errors.New("telefone deve ter DDD e 8 ou 9 dígitos")
+	ErrPhoneDDD    = errors.New("DDD inexistente")
+	ErrPhoneNumber = errors.New("número de telefone inválido")
+)
+
+// validDDDs são os códigos de área em uso no Brasil (plano de numeração da Anatel)
+var validDDDs = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true, "16": true, "17": true, "18": true, "19": true,
+	"21": true, "22": true, "24": true, "27": true, "28": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "37": true, "38": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "53": true, "54": true, "55": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true, "66": true, "67": true, "68": true, "69": true,
+	"71": true, "73": true, "74": true, "75": true, "77": true, "79": true,
+	"81": true, "82": true, "83": true, "84": true, "85": true, "86": true, "87": true, "88": true, "89": true,
+	"91": true, "92": true, "93": true, "94": true, "95": true, "96": true, "97": true, "98": true, "99": true,
+}
+
+// Phone é um telefone brasileiro já validado
+type Phone struct {
+	DDD    string
+	Number string // 9 dígitos para celular, 8 para fixo
+	Type   string // mobile ou landline
+}
+
+// ParsePhoneBR aceita telefones em formatos como "(11) 98765-4321", "11987654321", "+55 11 98765-4321"
+// ou "011 3456-7890", valida o DDD e classifica em celular ou fixo.
+// Celulares antigos com 8 dígitos recebem o nono dígito.
+func ParsePhoneBR(raw string) (Phone, error) {
+	digits := OnlyDigits(raw)
+	switch {
+	case len(digits) >= 12 && strings.HasPrefix(digits, "55"):
+		digits = digits[2:] // código do país
+	case strings.HasPrefix(digits, "0") && (len(digits) == 11 || len(digits) == 12):
+		digits = digits[1:] // prefixo de longa distância (0XX)
+	}
+	if len(digits) != 10 && len(digits) != 11 {
+		return Phone{}, ErrPhoneLength
+	}
+	ddd, number := digits[:2], digits[2:]
+	if !validDDDs[ddd] {
+		return Phone{}, ErrPhoneDDD
+	}
+	switch {
+	case len(number) == 9 && number[0] == '9':
+		return Phone{DDD: ddd, Number: number, Type: PhoneTypeMobile}, nil
+	case len(number) == 8 && number[0] >= '2' && number[0] <= '5':
+		return Phone{DDD: ddd, Number: number, Type: PhoneTypeLandline}, nil
+	case len(number) == 8 && number[0] >= '6':
+		return Phone{DDD: ddd, Number: "9" + number, Type: PhoneTypeMobile}, nil
+	}
+	return Phone{}, ErrPhoneNumber
+}
+
+// E164 retorna o telefone no formato internacional (+5511987654321)
+func (p Phone) E164() string {
+	return "+55" + p.DDD + p.Number
+}
+
+// Formatted retorna o telefone no formato nacional ((11) 98765-4321 ou (11) 3456-7890)
+func (p Phone) Formatted() string {
+	split := len(p.Number) - 4
+	return "(" + p.DDD + ") " + p.Number[:split] + "-" + p.Number[split:]
+}
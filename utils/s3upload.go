@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
@@ -27,6 +28,37 @@ type RealS3Uploader struct{}
 func (r *RealS3Uploader) UploadToS3(ctx context.Context, fileName string, file io.Reader) (string, error) {
 	bucketName := strings.TrimSpace(os.Getenv("AWS_BUCKET_NAME"))
 	bucketName = strings.Trim(bucketName, ".")
+	endpoint := strings.Trim(strings.TrimSpace(os.Getenv("AWS_ENDPOINT")), ".")
+	s3Client, err := newS3Client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Arquivos em disco (io.ReadSeeker) são enviados direto; outros leitores são lidos antes para a assinatura
+	body, ok := file.(io.ReadSeeker)
+	if !ok {
+		fileBytes, err := io.ReadAll(file)
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler arquivo: %w", err)
+		}
+		body = bytes.NewReader(fileBytes)
+	}
+
+	_, err = s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: &bucketName,
+		Key:    &fileName,
+		Body:   body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("erro ao enviar arquivo para S3: %w", err)
+	}
+
+	publicURL := fmt.Sprintf("https://%s.%s/%s", bucketName, endpoint, fileName)
+	return publicURL, nil
+}
+
+// newS3Client cria o cliente S3 com as credenciais e o endpoint das variáveis de ambiente AWS_*
+func newS3Client(ctx context.Context) (*s3.Client, error) {
 	endpoint := strings.TrimSpace(os.Getenv("AWS_ENDPOINT"))
 	endpoint = strings.Trim(endpoint, ".")
 	region := strings.TrimSpace(os.Getenv("AWS_REGION"))
@@ -38,7 +70,7 @@ func (r *RealS3Uploader) UploadToS3(ctx context.Context, fileName string, file i
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
 	)
 	if err != nil {
-		return "", fmt.Errorf("erro ao carregar config AWS: %w", err)
+		return nil, fmt.Errorf("erro ao carregar config AWS: %w", err)
 	}
 
 	customResolver := s3.EndpointResolverFunc(func(region string, options s3.EndpointResolverOptions) (aws.Endpoint, error) {
@@ -60,23 +92,42 @@ func (r *RealS3Uploader) UploadToS3(ctx context.Context, fileName string, file i
 		o.UsePathStyle = true // Necessário para Wasabi
 		o.HTTPClient = httpClient
 	})
+	return s3Client, nil
+}
+
+// S3Deleter define interface para remoção de arquivos do S3 (real ou mock)
+type S3Deleter interface {
+	DeleteFromS3(ctx context.Context, fileName string) error
+}
 
-	fileBytes, err := io.ReadAll(file)
+// RealS3Deleter implementa S3Deleter usando AWS SDK
+type RealS3Deleter struct{}
+
+func (r *RealS3Deleter) DeleteFromS3(ctx context.Context, fileName string) error {
+	bucketName := strings.Trim(strings.TrimSpace(os.Getenv("AWS_BUCKET_NAME")), ".")
+	s3Client, err := newS3Client(ctx)
 	if err != nil {
-		return "", fmt.Errorf("erro ao ler arquivo: %w", err)
+		return err
 	}
-
-	_, err = s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: &bucketName,
-		Key:    &fileName,
-		Body:   strings.NewReader(string(fileBytes)),
-	})
+	_, err = s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucketName, Key: &fileName})
 	if err != nil {
-		return "", fmt.Errorf("erro ao enviar arquivo para S3: %w", err)
+		return fmt.Errorf("erro ao remover arquivo do S3: %w", err)
 	}
+	return nil
+}
 
-	publicURL := fmt.Sprintf("https://%s.%s/%s", bucketName, endpoint, fileName)
-	return publicURL, nil
+// MockS3Deleter para testes automatizados (não remove nada)
+type MockS3Deleter struct {
+	Deleted     []string
+	ShouldError bool
+}
+
+func (m *MockS3Deleter) DeleteFromS3(ctx context.Context, fileName string) error {
+	if m.ShouldError {
+		return fmt.Errorf("erro simulado no mock S3")
+	}
+	m.Deleted = append(m.Deleted, fileName)
+	return nil
 }
 
 // MockS3Uploader para testes automatizados (não faz upload real)
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// legalSuffixes são termos de natureza jurídica que não distinguem uma empresa de outra
+// (comparados já sem acentos, em minúsculas e sem pontuação)
+var legalSuffixes = map[string]bool{
+	"ltda": true, "limitada": true, "me": true, "epp": true, "eireli": true, "mei": true,
+	"sa": true, "cia": true, "companhia": true, "ss": true, "slu": true,
+}
+
+// NormalizeName prepara um nome de cliente para comparação: minúsculas, sem acentos, sem pontuação e sem
+// sufixos como LTDA, ME, EIRELI e S/A. Ex.: "ACME Comércio Ltda." e "Acme comercio LTDA" ficam iguais.
+func NormalizeName(s string) string {
+	s = strings.ToLower(RemoveAccents(s))
+	// "s/a" e "s.a." viram "sa" antes de a pontuação ser trocada por espaço
+	s = strings.NewReplacer("s/a", "sa", "s.a.", "sa", "s.a", "sa").Replace(s)
+	words := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) })
+	out := words[:0]
+	for _, w := range words {
+		if !legalSuffixes[w] {
+			out = append(out, w)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// Levenshtein é a quantidade mínima de inserções, remoções e trocas de caracteres que transforma a em b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// LevenshteinSimilarity converte a distância de Levenshtein em uma nota de 0 (nada em comum) a 1 (iguais)
+func LevenshteinSimilarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// TrigramSimilarity compara os trigramas das palavras de a e b (como o pg_trgm do Postgres):
+// quantidade de trigramas em comum dividida pelo total de trigramas distintos, de 0 a 1
+func TrigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 && len(tb) == 0 {
+		return 1
+	}
+	common := 0
+	for t := range ta {
+		if tb[t] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(s) {
+		r := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			set[string(r[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// NameSimilarity compara dois nomes já normalizados por NormalizeName. Usa a maior nota entre trigramas
+// (que tolera palavras fora de ordem) e Levenshtein (que tolera erros de digitação em nomes curtos).
+func NameSimilarity(a, b string) float64 {
+	return max(TrigramSimilarity(a, b), LevenshteinSimilarity(a, b))
+}
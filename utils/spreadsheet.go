@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/extrame/xls"
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrSheetNotFound indica um índice de aba fora do arquivo
+var ErrSheetNotFound = errors.New("aba não encontrada")
+
+// SpreadsheetExtensions são as extensões de arquivo aceitas por OpenSpreadsheet
+var SpreadsheetExtensions = []string{".xlsx", ".xls", ".ods", ".csv", ".tsv"}
+
+// Spreadsheet é um arquivo de planilha aberto para leitura, independente do formato
+type Spreadsheet interface {
+	// SheetNames retorna os nomes das abas, na ordem do arquivo
+	SheetNames() []string
+	// Rows abre um iterador sobre as linhas da aba de índice informado (0 = primeira aba)
+	Rows(sheet int) (RowIterator, error)
+	Close() error
+}
+
+// RowIterator percorre as linhas de uma aba uma a uma, sem carregar a aba inteira em memória
+// (exceto nos formatos em que a biblioteca de leitura não permite, como .xls e .ods).
+//
+//	for it.Next() {
+//		row := it.Row()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type RowIterator interface {
+	Next() bool
+	Row() []string
+	Err() error
+	Close() error
+}
+
+// OpenSpreadsheet abre o arquivo conforme a extensão. Arquivos CSV/TSV são lidos como uma planilha de uma aba só,
+// com codificação (UTF-8, UTF-16 ou Windows-1252) e separador detectados automaticamente.
+// Em .xlsx e CSV as linhas são lidas sob demanda; .xls e .ods são carregados inteiros pelas bibliotecas.
+func OpenSpreadsheet(path, ext string) (Spreadsheet, error) {
+	switch strings.ToLower(ext) {
+	case ".xlsx":
+		f, err := excelize.OpenFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxSpreadsheet{f: f}, nil
+	case ".xls":
+		// O charset é ignorado pela biblioteca: o BIFF8 grava textos em UTF-16 ou Latin-1, ambos decodificados por ela
+		wb, err := xls.Open(path, "")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsSpreadsheet{wb: wb}, nil
+	case ".ods":
+		return openODS(path)
+	case ".csv":
+		return &csvSpreadsheet{path: path, name: "CSV"}, nil
+	case ".tsv":
+		return &csvSpreadsheet{path: path, name: "TSV", comma: '\t'}, nil
+	}
+	return nil, fmt.Errorf("formato de planilha não suportado: %s", ext)
+}
+
+// SelectSheets resolve quais abas devem ser lidas. selector pode ser o nome da aba ou sua posição
+// começando em 1; vazio seleciona a primeira aba. Com all=true todas as abas são selecionadas.
+func SelectSheets(names []string, selector string, all bool) ([]int, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("a planilha não possui abas")
+	}
+	if all {
+		idx := make([]int, len(names))
+		for i := range names {
+			idx[i] = i
+		}
+		return idx, nil
+	}
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return []int{0}, nil
+	}
+	for i, name := range names {
+		if strings.EqualFold(strings.TrimSpace(name), selector) {
+			return []int{i}, nil
+		}
+	}
+	if n, err := strconv.Atoi(selector); err == nil {
+		if n < 1 || n > len(names) {
+			return nil, fmt.Errorf("aba %d não existe: a planilha tem %d aba(s)", n, len(names))
+		}
+		return []int{n - 1}, nil
+	}
+	return nil, fmt.Errorf("aba '%s' não encontrada. Abas disponíveis: %s", selector, strings.Join(names, ", "))
+}
+
+type xlsxSpreadsheet struct {
+	f *excelize.File
+}
+
+func (s *xlsxSpreadsheet) SheetNames() []string {
+	return s.f.GetSheetList()
+}
+
+func (s *xlsxSpreadsheet) Rows(sheet int) (RowIterator, error) {
+	name := s.f.GetSheetName(sheet)
+	if name == "" {
+		return nil, ErrSheetNotFound
+	}
+	rows, err := s.f.Rows(name)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxRowIterator{rows: rows}, nil
+}
+
+func (s *xlsxSpreadsheet) Close() error {
+	return s.f.Close()
+}
+
+type xlsSpreadsheet struct {
+	wb *xls.WorkBook
+}
+
+func (s *xlsSpreadsheet) SheetNames() []string {
+	names := make([]string, 0, s.wb.NumSheets())
+	for i := 0; i < s.wb.NumSheets(); i++ {
+		if sheet := s.wb.GetSheet(i); sheet != nil {
+			names = append(names, sheet.Name)
+		}
+	}
+	return names
+}
+
+func (s *xlsSpreadsheet) Rows(sheet int) (RowIterator, error) {
+	ws := s.wb.GetSheet(sheet)
+	if ws == nil {
+		return nil, ErrSheetNotFound
+	}
+	return &xlsRowIterator{ws: ws, next: 0}, nil
+}
+
+func (s *xlsSpreadsheet) Close() error {
+	return nil
+}
+
+// xlsxRowIterator lê as linhas direto do XML da aba. Abas grandes ficam em arquivo temporário
+// (excelize.Options.UnzipXMLSizeLimit), de modo que só a linha corrente fica em memória.
+type xlsxRowIterator struct {
+	rows *excelize.Rows
+	row  []string
+	err  error
+}
+
+func (it *xlsxRowIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+	it.row, it.err = it.rows.Columns()
+	return it.err == nil
+}
+
+func (it *xlsxRowIterator) Row() []string {
+	return it.row
+}
+
+func (it *xlsxRowIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Error()
+}
+
+func (it *xlsxRowIterator) Close() error {
+	return it.rows.Close()
+}
+
+// xlsRowIterator monta cada linha só quando pedida; o arquivo .xls em si já foi carregado pela biblioteca
+// (o formato tem no máximo 65.536 linhas por aba)
+type xlsRowIterator struct {
+	ws   *xls.WorkSheet
+	next int
+	row  []string
+}
+
+func (it *xlsRowIterator) Next() bool {
+	if it.next > int(it.ws.MaxRow) {
+		return false
+	}
+	it.row = nil
+	if row := it.ws.Row(it.next); row != nil {
+		for j := 0; j < row.LastCol(); j++ {
+			it.row = append(it.row, row.Col(j))
+		}
+	}
+	it.next++
+	return true
+}
+
+func (it *xlsRowIterator) Row() []string {
+	return it.row
+}
+
+func (it *xlsRowIterator) Err() error {
+	return nil
+}
+
+func (it *xlsRowIterator) Close() error {
+	return nil
+}
+
+// sliceRowIterator percorre linhas já carregadas em memória
+type sliceRowIterator struct {
+	rows [][]string
+	next int
+}
+
+func (it *sliceRowIterator) Next() bool {
+	if it.next >= len(it.rows) {
+		return false
+	}
+	it.next++
+	return true
+}
+
+func (it *sliceRowIterator) Row() []string {
+	return it.rows[it.next-1]
+}
+
+func (it *sliceRowIterator) Err() error {
+	return nil
+}
+
+func (it *sliceRowIterator) Close() error {
+	return nil
+}
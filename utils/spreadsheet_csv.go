@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Codificações reconhecidas em arquivos de texto
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16   = "utf-16"
+	EncodingCP1252  = "windows-1252"
+	defaultCSVComma = ','
+	// csvSampleSize é o trecho do início do arquivo usado para detectar codificação e separador
+	csvSampleSize = 64 * 1024
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// csvDelimiters são os separadores aceitos, em ordem de preferência em caso de empate
+var csvDelimiters = []rune{';', ',', '\t', '|'}
+
+// DecodeText converte o conteúdo de um arquivo de texto para UTF-8 e informa a codificação detectada.
+// Arquivos com BOM (UTF-8 ou UTF-16) são decodificados conforme o BOM; sem BOM, conteúdo que não é
+// UTF-8 válido é tratado como Windows-1252 (superconjunto do Latin-1), padrão dos ERPs antigos no Windows.
+func DecodeText(data []byte) (string, string, error) {
+	enc := detectEncoding(data, true)
+	if enc == EncodingUTF8 {
+		return string(bytes.TrimPrefix(data, bomUTF8)), enc, nil
+	}
+	out, err := textDecoder(enc).Bytes(data)
+	return string(out), enc, err
+}
+
+// detectEncoding identifica a codificação pelo início do arquivo. complete indica que sample é o arquivo
+// inteiro; senão a última sequência UTF-8 pode ter sido cortada e não conta como inválida.
+// Um arquivo cujo início é ASCII puro e que só tem acentos em Windows-1252 depois do trecho lido é tratado como UTF-8.
+func detectEncoding(sample []byte, complete bool) string {
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		return EncodingUTF8
+	case bytes.HasPrefix(sample, bomUTF16LE), bytes.HasPrefix(sample, bomUTF16BE):
+		return EncodingUTF16
+	}
+	if !complete {
+		// descarta uma sequência multibyte incompleta no fim do trecho
+		for i := 1; i < utf8.UTFMax && i <= len(sample); i++ {
+			if utf8.RuneStart(sample[len(sample)-i]) {
+				if !utf8.FullRune(sample[len(sample)-i:]) {
+					sample = sample[:len(sample)-i]
+				}
+				break
+			}
+		}
+	}
+	if utf8.Valid(sample) {
+		return EncodingUTF8
+	}
+	return EncodingCP1252
+}
+
+func textDecoder(enc string) *encoding.Decoder {
+	switch enc {
+	case EncodingUTF16:
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()
+	case EncodingCP1252:
+		return charmap.Windows1252.NewDecoder()
+	}
+	return unicode.UTF8BOM.NewDecoder()
+}
+
+// DetectDelimiter escolhe o separador de um CSV pela primeira linha não vazia (normalmente o cabeçalho):
+// vence o candidato que mais aparece fora de aspas. Sem nenhum candidato, usa vírgula.
+func DetectDelimiter(text string) rune {
+	var line string
+	for _, l := range strings.Split(text, "\n") {
+		if strings.TrimSpace(l) != "" {
+			line = l
+			break
+		}
+	}
+	counts := map[rune]int{}
+	quoted := false
+	for _, r := range line {
+		if r == '"' {
+			quoted = !quoted
+			continue
+		}
+		if !quoted {
+			counts[r]++
+		}
+	}
+	best, bestCount := rune(defaultCSVComma), 0
+	for _, d := range csvDelimiters {
+		if counts[d] > bestCount {
+			best, bestCount = d, counts[d]
+		}
+	}
+	return best
+}
+
+// csvSpreadsheet expõe um arquivo CSV/TSV como uma planilha de aba única. comma=0 detecta o separador.
+type csvSpreadsheet struct {
+	path  string
+	name  string
+	comma rune
+}
+
+func (s *csvSpreadsheet) SheetNames() []string {
+	return []string{s.name}
+}
+
+// Rows abre o arquivo e lê um registro por vez, decodificando para UTF-8 durante a leitura
+func (s *csvSpreadsheet) Rows(sheet int) (RowIterator, error) {
+	if sheet != 0 {
+		return nil, ErrSheetNotFound
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewReaderSize(f, csvSampleSize)
+	sample, err := buf.Peek(csvSampleSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		f.Close()
+		return nil, err
+	}
+	enc := detectEncoding(sample, len(sample) < csvSampleSize)
+	comma := s.comma
+	if comma == 0 {
+		text, _ := textDecoder(enc).Bytes(sample)
+		comma = DetectDelimiter(string(text))
+	}
+	r := csv.NewReader(transform.NewReader(buf, textDecoder(enc)))
+	r.Comma = comma
+	r.FieldsPerRecord = -1 // linhas com quantidade variável de colunas, como nas planilhas
+	r.LazyQuotes = true
+	return &csvRowIterator{f: f, r: r}, nil
+}
+
+func (s *csvSpreadsheet) Close() error {
+	return nil
+}
+
+type csvRowIterator struct {
+	f   *os.File
+	r   *csv.Reader
+	row []string
+	err error
+}
+
+func (it *csvRowIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.row, it.err = it.r.Read()
+	return it.err == nil
+}
+
+func (it *csvRowIterator) Row() []string {
+	return it.row
+}
+
+func (it *csvRowIterator) Err() error {
+	if errors.Is(it.err, io.EOF) {
+		return nil
+	}
+	return it.err
+}
+
+func (it *csvRowIterator) Close() error {
+	return it.f.Close()
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Namespaces do OpenDocument usados no content.xml
+const (
+	odsTableNS  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsTextNS   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odsOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+)
+
+type odsSheet struct {
+	name string
+	rows [][]string
+}
+
+// odsSpreadsheet é uma planilha OpenDocument (.ods) já carregada em memória.
+// Planilhas muito grandes devem ser convertidas para .xlsx ou CSV, que são lidos sob demanda.
+type odsSpreadsheet struct {
+	sheets []odsSheet
+}
+
+func openODS(path string) (*odsSpreadsheet, error) {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+	for _, f := range z.File {
+		if f.Name != "content.xml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		sheets, err := parseODSContent(r)
+		if err != nil {
+			return nil, err
+		}
+		return &odsSpreadsheet{sheets: sheets}, nil
+	}
+	return nil, fmt.Errorf("arquivo ODS sem content.xml")
+}
+
+// parseODSContent lê as abas do content.xml. Linhas e células repetidas (table:number-rows-repeated e
+// table:number-columns-repeated) são expandidas, exceto as vazias no fim da aba ou da linha, que os
+// editores gravam para preencher a grade inteira.
+func parseODSContent(r io.Reader) ([]odsSheet, error) {
+	dec := xml.NewDecoder(r)
+	var (
+		sheets     []odsSheet
+		row        []string
+		cell       strings.Builder
+		inCell     bool
+		paragraphs int
+		cellValue  string
+		cellRepeat int
+		rowRepeat  int
+		emptyCells int
+		emptyRows  int
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsTableNS && t.Name.Local == "table":
+				sheets = append(sheets, odsSheet{name: odsAttr(t, odsTableNS, "name")})
+				emptyRows = 0
+			case t.Name.Space == odsTableNS && t.Name.Local == "table-row":
+				row, emptyCells = nil, 0
+				rowRepeat = odsRepeat(t, "number-rows-repeated")
+			case t.Name.Space == odsTableNS && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell, paragraphs = true, 0
+				cell.Reset()
+				cellValue = odsAttr(t, odsOfficeNS, "value")
+				cellRepeat = odsRepeat(t, "number-columns-repeated")
+			case inCell && t.Name.Space == odsTextNS:
+				switch t.Name.Local {
+				case "p":
+					if paragraphs > 0 {
+						cell.WriteString("\n")
+					}
+					paragraphs++
+				case "s":
+					n, err := strconv.Atoi(odsAttr(t, odsTextNS, "c"))
+					if err != nil || n < 1 {
+						n = 1
+					}
+					cell.WriteString(strings.Repeat(" ", n))
+				case "tab":
+					cell.WriteString("\t")
+				case "line-break":
+					cell.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			if inCell {
+				cell.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Space != odsTableNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "table-cell", "covered-table-cell":
+				inCell = false
+				v := cell.String()
+				if v == "" {
+					v = cellValue
+				}
+				if v == "" {
+					emptyCells += cellRepeat
+					continue
+				}
+				for ; emptyCells > 0; emptyCells-- {
+					row = append(row, "")
+				}
+				for i := 0; i < cellRepeat; i++ {
+					row = append(row, v)
+				}
+			case "table-row":
+				if len(sheets) == 0 {
+					continue
+				}
+				sheet := &sheets[len(sheets)-1]
+				if len(row) == 0 {
+					emptyRows += rowRepeat
+					continue
+				}
+				for ; emptyRows > 0; emptyRows-- {
+					sheet.rows = append(sheet.rows, nil)
+				}
+				for i := 0; i < rowRepeat; i++ {
+					sheet.rows = append(sheet.rows, append([]string(nil), row...))
+				}
+			}
+		}
+	}
+	return sheets, nil
+}
+
+func odsAttr(e xml.StartElement, space, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func odsRepeat(e xml.StartElement, attr string) int {
+	n, err := strconv.Atoi(odsAttr(e, odsTableNS, attr))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+func (s *odsSpreadsheet) SheetNames() []string {
+	names := make([]string, len(s.sheets))
+	for i, sheet := range s.sheets {
+		names[i] = sheet.name
+	}
+	return names
+}
+
+func (s *odsSpreadsheet) Rows(sheet int) (RowIterator, error) {
+	if sheet < 0 || sheet >= len(s.sheets) {
+		return nil, ErrSheetNotFound
+	}
+	return &sliceRowIterator{rows: s.sheets[sheet].rows}, nil
+}
+
+func (s *odsSpreadsheet) Close() error {
+	return nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// RemoveAccents remove os diacríticos de um texto (ex.: "Endereço" -> "Endereco")
+func RemoveAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return out
+}
+
+// SearchWords separa um texto de busca em palavras sem acentos, em minúsculas e sem pontuação
+func SearchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(RemoveAccents(s)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// PrefixTSQuery monta uma consulta de texto do Postgres (to_tsquery) em que cada palavra pode ser o início
+// de uma palavra do texto. Ex.: "Ana Silv" -> "ana:* & silv:*". Retorna "" se não houver palavras.
+func PrefixTSQuery(s string) string {
+	words := SearchWords(s)
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// EscapeLike protege os curingas % e _ de um texto usado em LIKE
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
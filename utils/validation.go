@@ -1,10 +1,121 @@
 package utils
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
 func IsValidEmail(email string) bool {
-	re := regexp.MustCompile(`^[a-zA-Z0-9._%%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	return re.MatchString(email)
+	return emailRegex.MatchString(email)
+}
+
+// Códigos de erro de validação devolvidos na API
+const (
+	CodeRequired        = "required"
+	CodeTooLong         = "too_long"
+	CodeInvalidEmail    = "invalid_email"
+	CodeInvalidPhone    = "invalid_phone"
+	CodeInvalidDocument = "invalid_document"
+	CodeInvalidCEP      = "invalid_cep"
+	CodeInvalidUF       = "invalid_uf"
+	CodeInvalidValue    = "invalid_value"
+)
+
+// FieldError descreve um problema de validação em um campo
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors agrupa os erros de validação de uma entidade
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, e := range v {
+		msgs = append(msgs, e.Field+": "+e.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validator acumula erros de validação. As regras são genéricas e podem ser usadas por qualquer entidade.
+type Validator struct {
+	errs ValidationErrors
+}
+
+// Add registra um erro de validação
+func (v *Validator) Add(field, code, message string) {
+	v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Errors retorna os erros acumulados, ou nil se a entidade for válida
+func (v *Validator) Errors() ValidationErrors {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Required exige que o valor não esteja vazio
+func (v *Validator) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, "Campo obrigatório")
+	}
+}
+
+// MaxLength limita a quantidade de caracteres do valor
+func (v *Validator) MaxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, CodeTooLong, fmt.Sprintf("Deve ter no máximo %d caracteres", max))
+	}
+}
+
+// Email valida a sintaxe do email, quando informado
+func (v *Validator) Email(field, value string) {
+	if value != "" && !IsValidEmail(value) {
+		v.Add(field, CodeInvalidEmail, "Email inválido")
+	}
+}
+
+// Phone valida um telefone brasileiro, quando informado
+func (v *Validator) Phone(field, value string) {
+	if value == "" {
+		return
+	}
+	if strings.Trim(value, "0123456789+()- .") != "" {
+		v.Add(field, CodeInvalidPhone, "Telefone contém caracteres inválidos")
+		return
+	}
+	if _, err := ParsePhoneBR(value); err != nil {
+		v.Add(field, CodeInvalidPhone, "Telefone inválido: "+err.Error())
+	}
+}
+
+// Document valida um CPF ou CNPJ, quando informado
+func (v *Validator) Document(field, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := ParseDocument(value); err != nil {
+		v.Add(field, CodeInvalidDocument, "CPF/CNPJ inválido: "+err.Error())
+	}
+}
+
+// CEP valida um CEP com 8 dígitos (com ou sem hífen), quando informado
+func (v *Validator) CEP(field, value string) {
+	if value != "" && !IsValidCEP(value) {
+		v.Add(field, CodeInvalidCEP, "CEP deve ter 8 dígitos")
+	}
+}
+
+// UF valida a sigla de uma unidade federativa, quando informada
+func (v *Validator) UF(field, value string) {
+	if value != "" && !IsValidUF(value) {
+		v.Add(field, CodeInvalidUF, "UF inexistente")
+	}
 }